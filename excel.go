@@ -1,6 +1,7 @@
 package main
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"log"
 	"reflect"
@@ -37,6 +38,9 @@ func writeToBuffer(xlsx *excelize.File, sheetName string, records []interface{})
 				column = columns[0]
 				columnName = columns[1]
 			}
+			if column == "" {
+				continue
+			}
 
 			if i == 0 {
 				A1 := fmt.Sprintf("%s%d", column, i+1)
@@ -62,7 +66,7 @@ func writeToBuffer(xlsx *excelize.File, sheetName string, records []interface{})
 					log.Println(err)
 				}
 			}
-			err = xlsx.SetCellValue(sheetName, fmt.Sprintf("%s%d", column, i+2), reflect.ValueOf(t).Elem().Field(j).Interface())
+			err = xlsx.SetCellValue(sheetName, fmt.Sprintf("%s%d", column, i+2), cellValue(reflect.ValueOf(t).Elem().Field(j)))
 			if err != nil {
 				log.Println(err)
 			}
@@ -71,6 +75,24 @@ func writeToBuffer(xlsx *excelize.File, sheetName string, records []interface{})
 	return xlsx, nil
 }
 
+// 生成的 model 中可空字段是指针或 sql.NullString 这类类型，取出实际的值
+func cellValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		return value
+	}
+	return v.Interface()
+}
+
 func getColumnJson(model interface{}) map[string]string {
 	columnJson := make(map[string]string)
 	d := reflect.TypeOf(model).Elem().Elem()
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteXlsxGeneratedModel(t *testing.T) {
+	type userModel struct {
+		Id     int64   `gorm:"column:id" xlsx:"A-主键"`
+		Name   *string `gorm:"column:name" xlsx:"B-用户名"`
+		Remark *string `gorm:"column:remark" xlsx:"C-备注"`
+	}
+	name := "fanone"
+	xlsx := excelize.NewFile()
+	if _, err := WriteXlsx(xlsx, defaultSheetName, []interface{}{&userModel{Id: 1, Name: &name}}); err != nil {
+		t.Fatal(err)
+	}
+	for cell, want := range map[string]string{"A1": "主键", "B1": "用户名", "A2": "1", "B2": "fanone", "C2": ""} {
+		got, err := xlsx.GetCellValue(defaultSheetName, cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s = %q, want %q", cell, got, want)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package gen
 
 const modsTemplate = `module {module}
 
@@ -21,6 +21,9 @@ mysql:
     password: "root"
     charset: "utf8mb4"
 
+# fgen model 生成到 repository/db/model 和 repository/db/dao
+layout: project
+
 redis:
   redisDbName: 1
   redisHost: 127.0.0.1
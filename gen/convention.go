@@ -0,0 +1,141 @@
+package gen
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/os/glog"
+)
+
+// 整数类型的时间字段的单位
+const (
+	TimeUnitSecond = ""      // 秒
+	TimeUnitMilli  = "milli" // 毫秒
+	TimeUnitNano   = "nano"  // 纳秒
+)
+
+// Conventions 按字段名约定生成 gorm 的软删除和自动维护的创建、更新时间，字段名支持通配符
+type Conventions struct {
+	SoftDelete []string `yaml:"softDelete"` // 软删除字段，时间类型生成 gorm.DeletedAt，整数类型生成 soft_delete.DeletedAt，tinyint 按 0、1 标记
+	CreateTime []string `yaml:"createTime"` // 创建时间，生成 autoCreateTime
+	UpdateTime []string `yaml:"updateTime"` // 更新时间，生成 autoUpdateTime
+	TimeUnit   string   `yaml:"timeUnit"`   // 整数类型的时间字段的单位，默认秒，字段名以 _ms、_ns 结尾时分别为毫秒、纳秒
+}
+
+// DefaultConventions 默认的约定
+func DefaultConventions() *Conventions {
+	return &Conventions{
+		SoftDelete: []string{"deleted_at"},
+		CreateTime: []string{"created_at"},
+		UpdateTime: []string{"updated_at"},
+	}
+}
+
+// 配置文件中设置了的约定覆盖默认的
+func mergeConventions(config *Conventions) *Conventions {
+	c := DefaultConventions()
+	if config == nil {
+		return c
+	}
+	if config.SoftDelete != nil {
+		c.SoftDelete = config.SoftDelete
+	}
+	if config.CreateTime != nil {
+		c.CreateTime = config.CreateTime
+	}
+	if config.UpdateTime != nil {
+		c.UpdateTime = config.UpdateTime
+	}
+	c.TimeUnit = config.TimeUnit
+	return c
+}
+
+// 按约定处理字段的类型，返回处理后的类型和额外的 gorm 标签，不符合约定时原样返回
+func (c *Conventions) apply(field *gdb.TableField, typeName string, logger *glog.Logger) (string, []string) {
+	if c == nil {
+		return typeName, nil
+	}
+	isTime := typeName == "time.Time"
+	isInt := isIntType(typeName)
+	unit := c.timeUnit(field.Name)
+	if matchColumn(field.Name, c.SoftDelete) {
+		switch {
+		case isTime:
+			return "gorm.DeletedAt", nil
+		case isFlagColumn(field, typeName):
+			// is_deleted tinyint 这样的标记字段只存 0、1，写入时间戳会溢出
+			return "soft_delete.DeletedAt", []string{"softDelete:flag"}
+		case isInt:
+			// 整数类型的软删除依赖 gorm.io/plugin/soft_delete，0 表示未删除
+			if unit == TimeUnitSecond {
+				return "soft_delete.DeletedAt", nil
+			}
+			return "soft_delete.DeletedAt", []string{"softDelete:" + unit}
+		}
+		logger.Warningf("soft delete column %s is %s, only time, integer and tinyint(1) columns are supported, skipped", field.Name, field.Type)
+		return typeName, nil
+	}
+	if !isTime && !isInt {
+		return typeName, nil
+	}
+	switch {
+	case matchColumn(field.Name, c.CreateTime):
+		return typeName, []string{autoTimeTag("autoCreateTime", isInt, unit)}
+	case matchColumn(field.Name, c.UpdateTime):
+		return typeName, []string{autoTimeTag("autoUpdateTime", isInt, unit)}
+	}
+	return typeName, nil
+}
+
+// 整数类型的时间字段的单位，字段名的后缀优先
+func (c *Conventions) timeUnit(column string) string {
+	lower := strings.ToLower(column)
+	switch {
+	case strings.HasSuffix(lower, "_ms") || strings.HasSuffix(lower, "_milli"):
+		return TimeUnitMilli
+	case strings.HasSuffix(lower, "_ns") || strings.HasSuffix(lower, "_nano"):
+		return TimeUnitNano
+	}
+	return c.TimeUnit
+}
+
+// autoCreateTime、autoCreateTime:milli，时间类型不需要单位
+func autoTimeTag(tag string, isInt bool, unit string) string {
+	if isInt && unit != TimeUnitSecond {
+		return tag + ":" + unit
+	}
+	return tag
+}
+
+func matchColumn(column string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.EqualFold(column, pattern) {
+			return true
+		}
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(column)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// 标记字段，tinyint 存不下时间戳，mysql 的 tinyint(1) 生成的是 bool
+// soft_delete 按整数读写，postgres 的 boolean 不支持
+func isFlagColumn(field *gdb.TableField, typeName string) bool {
+	switch strings.TrimPrefix(typeName, "*") {
+	case "int8", "uint8":
+		return true
+	case "bool":
+		return baseTypeName(field.Type) == "tinyint"
+	}
+	return false
+}
+
+func isIntType(typeName string) bool {
+	switch strings.TrimPrefix(typeName, "*") {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
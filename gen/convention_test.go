@@ -0,0 +1,62 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+)
+
+func TestConventions(t *testing.T) {
+	milli := mergeConventions(&Conventions{TimeUnit: TimeUnitMilli})
+	flag := mergeConventions(&Conventions{SoftDelete: []string{"deleted_at", "is_deleted"}})
+	cases := []struct {
+		conventions *Conventions
+		field       *gdb.TableField
+		typeName    string
+		tag         string
+	}{
+		{DefaultConventions(), &gdb.TableField{Name: "deleted_at", Type: "datetime", Null: true}, "gorm.DeletedAt", "column:deleted_at;type:datetime"},
+		{DefaultConventions(), &gdb.TableField{Name: "deleted_at", Type: "int unsigned"}, "soft_delete.DeletedAt", "column:deleted_at;type:int unsigned;not null"},
+		{DefaultConventions(), &gdb.TableField{Name: "deleted_at_ms", Type: "bigint"}, "int64", "column:deleted_at_ms;type:bigint;not null"},
+		{milli, &gdb.TableField{Name: "deleted_at", Type: "bigint"}, "soft_delete.DeletedAt", "column:deleted_at;type:bigint;not null;softDelete:milli"},
+		{DefaultConventions(), &gdb.TableField{Name: "created_at", Type: "datetime"}, "time.Time", "column:created_at;type:datetime;not null;autoCreateTime"},
+		{DefaultConventions(), &gdb.TableField{Name: "created_at", Type: "int"}, "int32", "column:created_at;type:int;not null;autoCreateTime"},
+		{milli, &gdb.TableField{Name: "created_at", Type: "bigint"}, "int64", "column:created_at;type:bigint;not null;autoCreateTime:milli"},
+		{DefaultConventions(), &gdb.TableField{Name: "updated_at", Type: "datetime(3)", Null: true}, "*time.Time", "column:updated_at;type:datetime(3);autoUpdateTime"},
+		{&Conventions{UpdateTime: []string{"update_time*"}}, &gdb.TableField{Name: "update_time_ns", Type: "bigint"}, "int64", "column:update_time_ns;type:bigint;not null;autoUpdateTime:nano"},
+		{DefaultConventions(), &gdb.TableField{Name: "updated_at", Type: "varchar(32)"}, "string", "column:updated_at;type:varchar(32);size:32;not null"},
+		{flag, &gdb.TableField{Name: "is_deleted", Type: "tinyint"}, "soft_delete.DeletedAt", "column:is_deleted;type:tinyint;not null;softDelete:flag"},
+		{flag, &gdb.TableField{Name: "is_deleted", Type: "tinyint(1) unsigned"}, "soft_delete.DeletedAt", "column:is_deleted;type:tinyint(1) unsigned;not null;softDelete:flag"},
+		{flag, &gdb.TableField{Name: "is_deleted", Type: "tinyint(1)", Default: "0"}, "soft_delete.DeletedAt", "column:is_deleted;type:tinyint(1);not null;default:0;softDelete:flag"},
+		{flag, &gdb.TableField{Name: "is_deleted", Type: "char(1)"}, "string", "column:is_deleted;type:char(1);size:1;not null"},
+		{&Conventions{}, &gdb.TableField{Name: "deleted_at", Type: "datetime", Null: true}, "*time.Time", "column:deleted_at;type:datetime"},
+	}
+	for _, c := range cases {
+		opt := &ModelOptions{Dialect: DialectMysql, Nullable: NullablePointer, Conventions: c.conventions}
+		as := genStructField(&tableMeta{Name: "user_info"}, c.field, opt)
+		if got := strings.TrimPrefix(as[1], " #"); got != c.typeName {
+			t.Errorf("%s: type = %s, want %s", c.field.Name, got, c.typeName)
+		}
+		if !strings.Contains(as[2], `gorm:"`+c.tag+`"`) {
+			t.Errorf("%s: tag = %s, want %s", c.field.Name, as[2], c.tag)
+		}
+	}
+}
+
+func TestMergeConventions(t *testing.T) {
+	c := mergeConventions(&Conventions{SoftDelete: []string{"is_deleted"}, TimeUnit: TimeUnitNano})
+	if len(c.SoftDelete) != 1 || c.SoftDelete[0] != "is_deleted" {
+		t.Errorf("soft delete = %v", c.SoftDelete)
+	}
+	if len(c.CreateTime) != 1 || c.CreateTime[0] != "created_at" || c.TimeUnit != TimeUnitNano {
+		t.Errorf("unexpected conventions: %+v", c)
+	}
+}
+
+func TestGenImportsSoftDelete(t *testing.T) {
+	imports := genImports("DeletedAt soft_delete.DeletedAt `gorm:\"column:deleted_at\"`", &ModelOptions{})
+	if len(imports) != 1 || imports[0] != "gorm.io/plugin/soft_delete" {
+		t.Errorf("imports = %v", imports)
+	}
+}
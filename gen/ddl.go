@@ -0,0 +1,571 @@
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+)
+
+const (
+	ddlTokenWord  = iota // 关键字、类型、数字、未加引号的标识符
+	ddlTokenIdent        // 反引号包裹的标识符
+	ddlTokenStr          // 单引号/双引号包裹的字符串
+	ddlTokenPunct        // 括号、逗号、分号等符号
+)
+
+type ddlToken struct {
+	kind int
+	text string
+}
+
+// 读取 ddl 文件，支持 migrations/*.sql 这种通配符，多个文件中的同名表以后面的为准
+func readDDLTables(pattern string) ([]*tableMeta, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("ddl file not found: %s", pattern)
+	}
+	var (
+		tables []*tableMeta
+		index  = make(map[string]int)
+	)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseDDL(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s failed: %v", file, err)
+		}
+		for _, table := range parsed {
+			if i, ok := index[table.Name]; ok {
+				tables[i] = table
+				continue
+			}
+			index[table.Name] = len(tables)
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+// 解析 mysql 的 CREATE TABLE 语句，其余语句直接忽略
+func parseDDL(content string) ([]*tableMeta, error) {
+	tokens, err := lexDDL(content)
+	if err != nil {
+		return nil, err
+	}
+	var tables []*tableMeta
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("create") {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && tokens[j].is("temporary") {
+			j++
+		}
+		if j >= len(tokens) || !tokens[j].is("table") {
+			continue
+		}
+		j++
+		if j+2 < len(tokens) && tokens[j].is("if") && tokens[j+1].is("not") && tokens[j+2].is("exists") {
+			j += 3
+		}
+		// 表名，可能带库名 db.table
+		if j >= len(tokens) {
+			return nil, fmt.Errorf("unexpected end of create table")
+		}
+		name := tokens[j].text
+		j++
+		for j+1 < len(tokens) && tokens[j].text == "." {
+			name = tokens[j+1].text
+			j += 2
+		}
+		if j >= len(tokens) || tokens[j].text != "(" {
+			// CREATE TABLE ... LIKE / AS SELECT 不支持
+			i = j
+			continue
+		}
+		end := matchParen(tokens, j)
+		if end < 0 {
+			return nil, fmt.Errorf("table %s: unbalanced parentheses", name)
+		}
+		table, err := parseDDLTable(name, tokens[j+1:end])
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+		i = end
+	}
+	return tables, nil
+}
+
+func parseDDLTable(name string, body []ddlToken) (*tableMeta, error) {
+	table := &tableMeta{
+		Name:   name,
+		Fields: make(map[string]*gdb.TableField),
+	}
+	for _, def := range splitDDL(body) {
+		if len(def) == 0 {
+			continue
+		}
+		if isDDLKeyDefinition(def) {
+			if index := parseDDLKey(def); index != nil {
+				table.Indexes = append(table.Indexes, index)
+			}
+			if fk := parseDDLForeignKey(name, def); fk != nil {
+				table.ForeignKeys = append(table.ForeignKeys, fk)
+			}
+			continue
+		}
+		// 字段上直接定义的外键 REFERENCES t(id)，去掉之后再解析字段，避免 ON UPDATE 被当成字段的属性
+		for i := range def {
+			if !def[i].is("references") || i < 2 {
+				continue
+			}
+			refTable, refColumns, end := parseDDLReferences(def[i:])
+			table.ForeignKeys = append(table.ForeignKeys, &foreignKey{
+				Name:       name + "_" + def[0].text + "_fkey",
+				Table:      name,
+				Columns:    []string{def[0].text},
+				RefTable:   refTable,
+				RefColumns: refColumns,
+			})
+			def = append(def[:i:i], def[i+end:]...)
+			break
+		}
+		field, err := parseDDLColumn(def)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %v", name, err)
+		}
+		field.Index = len(table.Fields)
+		table.Fields[field.Name] = field
+		// 字段上直接定义的主键、唯一索引
+		switch field.Key {
+		case "PRI":
+			table.Indexes = append(table.Indexes, &Index{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{field.Name}})
+		case "UNI":
+			table.Indexes = append(table.Indexes, &Index{Name: field.Name, Unique: true, Columns: []string{field.Name}})
+		}
+	}
+	markIndexKeys(table.Fields, table.Indexes)
+	return table, nil
+}
+
+// 解析单个字段定义，如 `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键'
+func parseDDLColumn(def []ddlToken) (*gdb.TableField, error) {
+	if len(def) < 2 {
+		return nil, fmt.Errorf("invalid column definition: %s", joinDDL(def))
+	}
+	field := &gdb.TableField{
+		Name: def[0].text,
+		Null: true,
+	}
+	i := 1
+	typeName := strings.ToLower(def[i].text)
+	i++
+	// 类型参数，如 varchar(64)、decimal(10,2)、enum('a','b')
+	if i < len(def) && def[i].text == "(" {
+		end := matchParen(def, i)
+		if end < 0 {
+			return nil, fmt.Errorf("column %s: unbalanced parentheses", field.Name)
+		}
+		typeName += "(" + joinDDL(def[i+1:end]) + ")"
+		i = end + 1
+	}
+	for i < len(def) && (def[i].is("unsigned") || def[i].is("zerofill")) {
+		typeName += " " + strings.ToLower(def[i].text)
+		i++
+	}
+	field.Type = typeName
+
+	for ; i < len(def); i++ {
+		tok := def[i]
+		switch {
+		case tok.is("not") && i+1 < len(def) && def[i+1].is("null"):
+			field.Null = false
+			i++
+		case tok.is("null"):
+			field.Null = true
+		case tok.is("default") && i+1 < len(def):
+			i++
+			switch {
+			case def[i].is("null"):
+				field.Default = nil
+			case def[i].text == "(":
+				// 表达式默认值 DEFAULT (uuid())
+				end := matchParen(def, i)
+				if end < 0 {
+					end = len(def) - 1
+				}
+				field.Default = joinDDL(def[i+1 : end])
+				i = end
+			case def[i].text == "-" && i+1 < len(def):
+				field.Default = "-" + def[i+1].text
+				i++
+			case def[i].kind == ddlTokenWord && i+1 < len(def) && def[i+1].kind == ddlTokenStr:
+				// b'0'、x'0A'、_utf8mb4'abc' 这样带前缀的字符串，和 information_schema 中的一样保留前缀和引号
+				field.Default = def[i].text + "'" + def[i+1].text + "'"
+				i++
+			default:
+				field.Default = def[i].text
+				// CURRENT_TIMESTAMP(3)
+				if i+1 < len(def) && def[i+1].text == "(" {
+					if end := matchParen(def, i+1); end > 0 {
+						field.Default = def[i].text + "(" + joinDDL(def[i+2:end]) + ")"
+						i = end
+					}
+				}
+			}
+		case tok.is("auto_increment"):
+			field.Extra = appendExtra(field.Extra, "auto_increment")
+		case tok.is("on") && i+2 < len(def) && def[i+1].is("update"):
+			field.Extra = appendExtra(field.Extra, "on update "+def[i+2].text)
+			i += 2
+			if i+1 < len(def) && def[i+1].text == "(" {
+				if end := matchParen(def, i+1); end > 0 {
+					i = end
+				}
+			}
+		case tok.is("comment") && i+1 < len(def):
+			field.Comment = def[i+1].text
+			i++
+		case tok.is("primary") && i+1 < len(def) && def[i+1].is("key"):
+			field.Key = "PRI"
+			field.Null = false
+			i++
+		case tok.is("unique"):
+			if field.Key == "" {
+				field.Key = "UNI"
+			}
+			if i+1 < len(def) && def[i+1].is("key") {
+				i++
+			}
+		case tok.is("key"):
+			// 字段定义中单独的 KEY 等同于 PRIMARY KEY
+			field.Key = "PRI"
+			field.Null = false
+		}
+	}
+	return field, nil
+}
+
+func isDDLKeyDefinition(def []ddlToken) bool {
+	if def[0].kind != ddlTokenWord {
+		return false
+	}
+	switch strings.ToLower(def[0].text) {
+	case "primary", "unique", "key", "index", "constraint", "fulltext", "spatial", "foreign", "check":
+		return true
+	}
+	return false
+}
+
+// 解析表级别的索引定义，未命名的索引和 mysql 一样以第一个字段命名
+func parseDDLKey(def []ddlToken) *Index {
+	i := 0
+	if def[i].is("constraint") {
+		i++
+		if i < len(def) && !def[i].is("primary") && !def[i].is("unique") && !def[i].is("foreign") && !def[i].is("check") {
+			i++
+		}
+	}
+	if i >= len(def) {
+		return nil
+	}
+	index := &Index{}
+	switch {
+	case def[i].is("primary"):
+		index.Name = "PRIMARY"
+		index.Primary = true
+		index.Unique = true
+	case def[i].is("unique"):
+		index.Unique = true
+	case def[i].is("key"), def[i].is("index"):
+	default:
+		// FULLTEXT/SPATIAL/FOREIGN KEY/CHECK 不影响字段类型
+		return nil
+	}
+	i++
+	for i < len(def) && def[i].text != "(" {
+		// 索引名，跳过 KEY/INDEX/USING BTREE 等关键字
+		switch {
+		case def[i].is("using"):
+			i++
+		case !index.Primary && def[i].kind != ddlTokenPunct && !def[i].is("key") && !def[i].is("index"):
+			index.Name = def[i].text
+		}
+		i++
+	}
+	if i >= len(def) {
+		return nil
+	}
+	end := matchParen(def, i)
+	if end < 0 {
+		return nil
+	}
+	index.Columns = ddlKeyColumns(def[i+1 : end])
+	if len(index.Columns) == 0 {
+		return nil
+	}
+	if index.Name == "" {
+		index.Name = index.Columns[0]
+	}
+	return index
+}
+
+// 解析表级别的外键 [CONSTRAINT name] FOREIGN KEY [name] (cols) REFERENCES t (cols)
+func parseDDLForeignKey(table string, def []ddlToken) *foreignKey {
+	fk := &foreignKey{Table: table}
+	i := 0
+	if def[i].is("constraint") {
+		i++
+		if i < len(def) && !def[i].is("foreign") {
+			fk.Name = def[i].text
+			i++
+		}
+	}
+	if i+1 >= len(def) || !def[i].is("foreign") || !def[i+1].is("key") {
+		return nil
+	}
+	for i += 2; i < len(def) && def[i].text != "("; i++ {
+		if fk.Name == "" && def[i].kind != ddlTokenPunct {
+			fk.Name = def[i].text
+		}
+	}
+	end := matchParen(def, i)
+	if end < 0 {
+		return nil
+	}
+	fk.Columns = ddlKeyColumns(def[i+1 : end])
+	if end+1 >= len(def) || !def[end+1].is("references") {
+		return nil
+	}
+	fk.RefTable, fk.RefColumns, _ = parseDDLReferences(def[end+1:])
+	if fk.Name == "" {
+		fk.Name = table + "_" + strings.Join(fk.Columns, "_") + "_fkey"
+	}
+	return fk
+}
+
+// 解析 REFERENCES t [(cols)] [MATCH x] [ON DELETE/UPDATE action] [[NOT] DEFERRABLE] [INITIALLY x]，
+// 返回引用的表、字段和子句的长度，没有写引用字段时返回空，表示引用的是主键
+func parseDDLReferences(tokens []ddlToken) (string, []string, int) {
+	i := 1
+	if i >= len(tokens) {
+		return "", nil, i
+	}
+	table := tokens[i].text
+	i++
+	for i+1 < len(tokens) && tokens[i].text == "." {
+		table = tokens[i+1].text
+		i += 2
+	}
+	var columns []string
+	if i < len(tokens) && tokens[i].text == "(" {
+		if end := matchParen(tokens, i); end > 0 {
+			columns = ddlKeyColumns(tokens[i+1 : end])
+			i = end + 1
+		}
+	}
+	for i < len(tokens) {
+		switch {
+		case tokens[i].is("match") || tokens[i].is("initially"):
+			i += 2
+		case tokens[i].is("on") && i+2 < len(tokens) && (tokens[i+1].is("delete") || tokens[i+1].is("update")):
+			i += 3
+			// SET NULL、SET DEFAULT、NO ACTION
+			if i < len(tokens) && (tokens[i-1].is("set") || tokens[i-1].is("no")) {
+				i++
+			}
+		case tokens[i].is("deferrable"):
+			i++
+		case tokens[i].is("not") && i+1 < len(tokens) && tokens[i+1].is("deferrable"):
+			i += 2
+		default:
+			return table, columns, i
+		}
+	}
+	return table, columns, i
+}
+
+// 索引中的字段列表，忽略前缀长度和 ASC/DESC
+func ddlKeyColumns(tokens []ddlToken) []string {
+	var columns []string
+	for _, part := range splitDDL(tokens) {
+		if len(part) > 0 {
+			columns = append(columns, part[0].text)
+		}
+	}
+	return columns
+}
+
+// 按最外层的逗号切分
+func splitDDL(tokens []ddlToken) [][]ddlToken {
+	var (
+		parts [][]ddlToken
+		depth int
+		start int
+	)
+	for i, tok := range tokens {
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 && tok.kind == ddlTokenPunct {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// 返回与 tokens[start] 的左括号匹配的右括号位置
+func matchParen(tokens []ddlToken, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].kind != ddlTokenPunct {
+			continue
+		}
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// 还原类型参数，字符串重新加上单引号
+func joinDDL(tokens []ddlToken) string {
+	var builder strings.Builder
+	for i, tok := range tokens {
+		if i > 0 && tok.kind != ddlTokenPunct && tokens[i-1].kind != ddlTokenPunct {
+			builder.WriteString(" ")
+		}
+		if tok.kind == ddlTokenStr {
+			builder.WriteString("'" + strings.ReplaceAll(tok.text, "'", "''") + "'")
+			continue
+		}
+		builder.WriteString(tok.text)
+	}
+	return builder.String()
+}
+
+func appendExtra(extra, s string) string {
+	if extra == "" {
+		return s
+	}
+	return extra + " " + s
+}
+
+func (t ddlToken) is(word string) bool {
+	return t.kind == ddlTokenWord && strings.EqualFold(t.text, word)
+}
+
+// 词法分析，去掉注释
+func lexDDL(content string) ([]ddlToken, error) {
+	var (
+		tokens []ddlToken
+		runes  = []rune(content)
+	)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || (c == '-' && i+1 < len(runes) && runes[i+1] == '-'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += 2
+		case c == '\'' || c == '"' || c == '`':
+			var (
+				builder strings.Builder
+				closed  bool
+			)
+			i++
+			for i < len(runes) {
+				if runes[i] == '\\' && c != '`' && i+1 < len(runes) {
+					builder.WriteRune(unescapeDDL(runes[i+1]))
+					i += 2
+					continue
+				}
+				if runes[i] == c {
+					// 连续两个引号表示转义
+					if i+1 < len(runes) && runes[i+1] == c {
+						builder.WriteRune(c)
+						i += 2
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+			kind := ddlTokenStr
+			if c == '`' {
+				kind = ddlTokenIdent
+			}
+			tokens = append(tokens, ddlToken{kind: kind, text: builder.String()})
+		case isDDLWordRune(c):
+			start := i
+			// 数字允许带小数点，如 DEFAULT 0.00
+			number := c >= '0' && c <= '9'
+			for i < len(runes) && (isDDLWordRune(runes[i]) || number && runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlTokenWord, text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, ddlToken{kind: ddlTokenPunct, text: string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isDDLWordRune(c rune) bool {
+	return c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c > 127
+}
+
+func unescapeDDL(c rune) rune {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	default:
+		return c
+	}
+}
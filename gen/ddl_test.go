@@ -0,0 +1,175 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/os/glog"
+)
+
+func TestParseDDL(t *testing.T) {
+	content, err := ioutil.ReadFile("testdata/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := parseDDL(string(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 7 || tables[0].Name != "user_info" || tables[1].Name != "tag" || tables[2].Name != "user_role" {
+		t.Fatalf("unexpected tables: %+v", tables)
+	}
+
+	fields := tables[0].Fields
+	if len(fields) != 7 {
+		t.Fatalf("unexpected field count: %d", len(fields))
+	}
+	id := fields["id"]
+	if id.Index != 0 || id.Type != "bigint(20) unsigned" || id.Key != "PRI" || id.Null || id.Extra != "auto_increment" || id.Comment != "主键" {
+		t.Errorf("unexpected id: %+v", id)
+	}
+	email := fields["email"]
+	if email.Key != "UNI" || email.Default != "" || email.Comment != "邮箱, 唯一" {
+		t.Errorf("unexpected email: %+v", email)
+	}
+	if nick := fields["nick_name"]; !nick.Null || nick.Default != nil {
+		t.Errorf("unexpected nick_name: %+v", nick)
+	}
+	if status := fields["status"]; status.Type != "enum('draft','published')" || status.Default != "draft" || status.Key != "" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if balance := fields["balance"]; balance.Type != "decimal(10,2)" || balance.Default != "0.00" {
+		t.Errorf("unexpected balance: %+v", balance)
+	}
+	if org := fields["org_id"]; org.Key != "MUL" || org.Index != 5 {
+		t.Errorf("unexpected org_id: %+v", org)
+	}
+	if updated := fields["updated_at"]; updated.Default != "CURRENT_TIMESTAMP(3)" || updated.Extra != "on update CURRENT_TIMESTAMP" {
+		t.Errorf("unexpected updated_at: %+v", updated)
+	}
+
+	var names []string
+	for _, index := range tables[0].Indexes {
+		names = append(names, index.Name+"("+strings.Join(index.Columns, ",")+")")
+	}
+	if got := strings.Join(names, " "); got != "PRIMARY(id) uk_email(email) idx_org_status(org_id,status)" {
+		t.Errorf("unexpected indexes: %s", got)
+	}
+
+	tag := tables[1].Fields
+	if tag["id"].Key != "PRI" || tag["name"].Key != "UNI" {
+		t.Errorf("unexpected tag fields: %+v %+v", tag["id"], tag["name"])
+	}
+}
+
+func TestParseDDLDefaultLiteral(t *testing.T) {
+	tables, err := parseDDL("CREATE TABLE `flag` (`id` int NOT NULL, `enabled` bit(1) NOT NULL DEFAULT b'0', `mask` binary(1) DEFAULT x'0A', PRIMARY KEY (`id`));")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("unexpected tables: %+v", tables)
+	}
+	fields := tables[0].Fields
+	if enabled := fields["enabled"]; enabled.Default != "b'0'" || enabled.Null {
+		t.Errorf("unexpected enabled: %+v", enabled)
+	}
+	if mask := fields["mask"]; mask.Default != "x'0A'" || !mask.Null {
+		t.Errorf("unexpected mask: %+v", mask)
+	}
+}
+
+func TestGenModelFromDDL(t *testing.T) {
+	genPath := filepath.Join(t.TempDir(), "dao")
+	opts := ModelOptions{DDL: "testdata/*.sql", Path: genPath, Tables: []string{"user_info"}}
+	summary, err := GenModel(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Created) != 3 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	// 同一个包中生成时不生成 init.go，避免和已有的 _db 冲突
+	if gfile.Exists(filepath.Join(genPath, "init.go")) {
+		t.Error("init.go should not be generated in single layout")
+	}
+	content, err := ioutil.ReadFile(filepath.Join(genPath, "user_info_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "type UserInfoModel struct") {
+		t.Errorf("unexpected content:\n%s", content)
+	}
+	opts.Tables = []string{"not_exist", "user_info"}
+	opts.Overwrite = OverwriteForce
+	summary, err = GenModel(context.Background(), opts)
+	if !errors.Is(err, ErrTableNotFound) {
+		t.Fatalf("err = %v, want ErrTableNotFound", err)
+	}
+	var tableErr *TableError
+	if !errors.As(err, &tableErr) || tableErr.Table != "not_exist" || tableErr.Op != OpIntrospect {
+		t.Errorf("unexpected table error: %+v", tableErr)
+	}
+	if len(summary.Unchanged) != 3 {
+		t.Errorf("other tables should still be generated: %+v", summary)
+	}
+}
+
+func TestGenModelLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := glog.New()
+	logger.SetWriter(&buf)
+	genPath := filepath.Join(t.TempDir(), "dao")
+	opts := ModelOptions{DDL: "testdata/*.sql", Path: genPath, Tables: []string{"access_log"}, Logger: logger}
+	if _, err := GenModel(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"table access_log has no primary key", "generated: " + filepath.Join(genPath, "access_log_gen.go")} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestGenModelCheck(t *testing.T) {
+	genPath := filepath.Join(t.TempDir(), "dao")
+	opts := ModelOptions{DDL: "testdata/*.sql", Path: genPath, Tables: []string{"user_info"}}
+	check := opts
+	check.Check = true
+	if _, err := GenModel(context.Background(), check); !errors.Is(err, ErrOutOfDate) {
+		t.Error("check should fail when the model file does not exist")
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(genPath, "user_info_gen.go")); err == nil {
+		t.Error("check should not write files")
+	}
+
+	if _, err := GenModel(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenModel(context.Background(), check); err != nil {
+		t.Errorf("check should pass after generating: %v", err)
+	}
+
+	path := filepath.Join(genPath, "user_info_gen.go")
+	if err := ioutil.WriteFile(path, []byte("package dao\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status := previewModelFile(path, "package dao\n\ntype A struct{}\n", &ModelOptions{}); status != fileOverwritten {
+		t.Errorf("status = %s, want %s", status, fileOverwritten)
+	}
+	var diff bytes.Buffer
+	check.Diff = true
+	check.Output = &diff
+	if _, err := GenModel(context.Background(), check); !errors.Is(err, ErrOutOfDate) {
+		t.Error("check should fail when the model file differs")
+	}
+	if !strings.Contains(diff.String(), "+type UserInfoModel struct") {
+		t.Errorf("unexpected diff:\n%s", diff.String())
+	}
+}
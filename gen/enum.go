@@ -0,0 +1,188 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gogf/gf/os/glog"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// 枚举的类型
+const (
+	EnumString = "string" // mysql 的 enum，生成字符串类型和 Valid 方法
+	EnumSet    = "set"    // mysql 的 set，值为逗号分隔的多个成员
+	EnumInt    = "int"    // 整数字段的注释约定，如 状态: 1-待支付 2-已支付，生成整数类型和 String 方法
+)
+
+// Enum 根据字段生成的枚举类型
+type Enum struct {
+	Kind     string       // string、set、int
+	Name     string       // 类型名，如 UserInfoStatus
+	Column   string       // 字段名，如 status
+	BaseType string       // 底层类型，如 string、int8
+	Values   []*EnumValue // 按定义顺序排列的值
+}
+
+// EnumValue 枚举的一个值
+type EnumValue struct {
+	Name  string // 常量名，如 UserInfoStatusDraft
+	Value string // 常量值，字符串已加上引号，如 "draft"、1
+	Label string // 注释中的说明，如 待支付，mysql 的 enum 为原值
+}
+
+// 注释中的枚举值，如 1-待支付、2:已支付、3=cancelled
+var commentEnumRegex = regexp.MustCompile(`(-?\d+)\s*[-:=：]\s*([^\s,，;；、]+)`)
+
+// 按字段类型和注释生成枚举，不是枚举时返回 nil
+func genEnum(name, column, dbType, typeName, comment string, logger *glog.Logger) *Enum {
+	t := strings.ToLower(baseTypeName(dbType))
+	switch {
+	case (t == "enum" || t == "set") && typeName == "string":
+		values := parseEnumValues(dbType)
+		if len(values) == 0 {
+			return nil
+		}
+		enum := &Enum{Kind: EnumString, Name: name, Column: column, BaseType: "string"}
+		if t == "set" {
+			enum.Kind = EnumSet
+		}
+		for i, v := range values {
+			enum.Values = append(enum.Values, &EnumValue{
+				Name:  enumValueName(name, v, strconv.Itoa(i+1)),
+				Value: strconv.Quote(v),
+				Label: v,
+			})
+		}
+		return enum.dedupe()
+	case isIntType(typeName):
+		return parseCommentEnum(name, column, typeName, comment, logger)
+	}
+	return nil
+}
+
+// 解析 enum('draft','published') 中的值，支持两个单引号和反斜杠的转义
+func parseEnumValues(dbType string) []string {
+	start, end := strings.Index(dbType, "("), strings.LastIndex(dbType, ")")
+	if start < 0 || end < start {
+		return nil
+	}
+	var (
+		values []string
+		buf    strings.Builder
+		quoted bool
+	)
+	s := dbType[start+1 : end]
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case !quoted && c == '\'':
+			quoted = true
+			buf.Reset()
+		case quoted && c == '\\' && i+1 < len(s):
+			i++
+			buf.WriteByte(s[i])
+		case quoted && c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+			buf.WriteByte('\'')
+		case quoted && c == '\'':
+			quoted = false
+			values = append(values, buf.String())
+		case quoted:
+			buf.WriteByte(c)
+		}
+	}
+	return values
+}
+
+// 解析注释中的枚举，如 状态: 1-待支付 2-已支付 3-已取消，冒号后面需要全部是枚举值，至少两个
+func parseCommentEnum(name, column, typeName, comment string, logger *glog.Logger) *Enum {
+	i := strings.IndexAny(comment, ":：")
+	if i < 0 {
+		return nil
+	}
+	_, size := utf8.DecodeRuneInString(comment[i:])
+	rest := strings.TrimSpace(comment[i+size:])
+	matches := commentEnumRegex.FindAllStringSubmatch(rest, -1)
+	if len(matches) < 2 {
+		return nil
+	}
+	// 去掉匹配到的值之后只能剩下分隔符，否则不是约定的格式
+	if strings.Trim(commentEnumRegex.ReplaceAllString(rest, ""), " \t,，;；、") != "" {
+		return nil
+	}
+	enum := &Enum{Kind: EnumInt, Name: name, Column: column, BaseType: strings.TrimPrefix(typeName, "*")}
+	for _, m := range matches {
+		// 值超出字段类型的范围时生成的常量不能编译，如 unsigned 字段的 -1
+		if !intInRange(m[1], enum.BaseType) {
+			logger.Warningf("enum value %s of column %s overflows %s, the enum is not generated", m[1], column, enum.BaseType)
+			return nil
+		}
+		enum.Values = append(enum.Values, &EnumValue{
+			Name:  enumValueName(name, m[2], strings.Replace(m[1], "-", "Neg", 1)),
+			Value: m[1],
+			Label: m[2],
+		})
+	}
+	return enum.dedupe()
+}
+
+// 整数值是否在类型的范围内
+func intInRange(value, typeName string) bool {
+	bits := 64
+	if i := strings.IndexAny(typeName, "0123456789"); i >= 0 {
+		bits, _ = strconv.Atoi(typeName[i:])
+	}
+	var err error
+	if strings.HasPrefix(typeName, "uint") {
+		_, err = strconv.ParseUint(value, 10, bits)
+	} else {
+		_, err = strconv.ParseInt(value, 10, bits)
+	}
+	return err == nil
+}
+
+// 常量名，值不能作为标识符时（如中文）用序号或者整数值，如 UserInfoStatus1
+func enumValueName(name, value, fallback string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	if len(words) == 0 || !unicode.IsLetter(rune(words[0][0])) {
+		return name + fallback
+	}
+	return name + gstr.CaseCamel(strings.Join(words, "_"))
+}
+
+// 常量名重复时加上序号
+func (e *Enum) dedupe() *Enum {
+	used := make(map[string]bool, len(e.Values))
+	for i, v := range e.Values {
+		if used[v.Name] {
+			v.Name = fmt.Sprintf("%s%d", v.Name, i+1)
+		}
+		used[v.Name] = true
+	}
+	return e
+}
+
+// 枚举用到的包
+func enumImports(enums []*Enum) []string {
+	var imports []string
+	for _, enum := range enums {
+		var path string
+		switch enum.Kind {
+		case EnumSet:
+			path = "strings"
+		case EnumInt:
+			path = "strconv"
+		}
+		if path != "" && !gstr.InArray(imports, path) {
+			imports = append(imports, path)
+		}
+	}
+	return imports
+}
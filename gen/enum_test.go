@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/os/glog"
+)
+
+func TestParseEnumValues(t *testing.T) {
+	cases := map[string]string{
+		"enum('draft','published')":  "draft|published",
+		"ENUM('it''s', 'a\\'b', '')": "it's|a'b|",
+		"set('slow','error','a,b')":  "slow|error|a,b",
+		"enum()":                     "",
+	}
+	for dbType, want := range cases {
+		if got := strings.Join(parseEnumValues(dbType), "|"); got != want {
+			t.Errorf("%s: values = %s, want %s", dbType, got, want)
+		}
+	}
+}
+
+func TestGenEnum(t *testing.T) {
+	cases := []struct {
+		dbType   string
+		typeName string
+		comment  string
+		want     string
+	}{
+		{"enum('draft','in-review','草稿')", "string", "", "string: OrderStatusDraft=\"draft\" OrderStatusInReview=\"in-review\" OrderStatus3=\"草稿\""},
+		{"set('a','b')", "string", "", "set: OrderStatusA=\"a\" OrderStatusB=\"b\""},
+		{"tinyint", "int8", "状态: 1-待支付 2-已支付 3-已取消", "int: OrderStatus1=1 OrderStatus2=2 OrderStatus3=3"},
+		{"int", "int32", "状态：0=unpaid，1=paid(已支付)，-1=已关闭", "int: OrderStatusUnpaid=0 OrderStatusPaid=1 OrderStatusNeg1=-1"},
+		{"int", "int32", "状态: 1-待支付", ""},
+		{"int", "int32", "状态: 1-待支付 2-已支付，默认为 1", ""},
+		{"varchar(16)", "string", "状态: 1-待支付 2-已支付", ""},
+		{"tinyint(1)", "bool", "是否删除: 0-否 1-是", ""},
+		// 超出类型范围的值不生成枚举
+		{"tinyint unsigned", "uint8", "状态: -1-已关闭 1-待支付", ""},
+		{"tinyint", "int8", "状态: 1-待支付 200-已支付", ""},
+		{"int unsigned", "*uint32", "状态: 0-待支付 4294967295-已支付", "int: OrderStatus0=0 OrderStatus4294967295=4294967295"},
+	}
+	for _, c := range cases {
+		var got string
+		if enum := genEnum("OrderStatus", "status", c.dbType, c.typeName, c.comment, glog.DefaultLogger()); enum != nil {
+			values := make([]string, 0, len(enum.Values))
+			for _, v := range enum.Values {
+				values = append(values, v.Name+"="+v.Value)
+			}
+			got = enum.Kind + ": " + strings.Join(values, " ")
+		}
+		if got != c.want {
+			t.Errorf("%s %s: enum = %s, want %s", c.dbType, c.comment, got, c.want)
+		}
+	}
+}
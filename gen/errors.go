@@ -0,0 +1,122 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrTableNotFound 表在数据库或者 ddl 文件中不存在
+	ErrTableNotFound = errors.New("table not found")
+	// ErrOutOfDate check 模式下生成的内容和已有的文件不一致
+	ErrOutOfDate = errors.New("model files are out of date")
+	// ErrLegacyFile 旧版本生成的完整文件和 *_gen.go 重复声明了 model，需要 -force 或者 -backup 替换
+	ErrLegacyFile = errors.New("file is generated by an older fgen")
+)
+
+// 表在某个阶段的操作
+const (
+	OpIntrospect = "introspect" // 读取表结构
+	OpGenerate   = "generate"   // 生成代码
+	OpWrite      = "write"      // 写入文件
+)
+
+// TableError 单个表生成失败的错误
+type TableError struct {
+	Table string
+	Op    string
+	Err   error
+}
+
+func (e *TableError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Table, e.Err)
+}
+
+func (e *TableError) Unwrap() error {
+	return e.Err
+}
+
+// GenError 汇总所有表的错误，一个表失败不影响其他表的生成
+type GenError struct {
+	Errors []*TableError
+}
+
+func (e *GenError) add(table, op string, err error) {
+	e.Errors = append(e.Errors, &TableError{Table: table, Op: op, Err: err})
+}
+
+func (e *GenError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d tables failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Is 任意一个表的错误匹配即可，如 errors.Is(err, ErrTableNotFound)
+func (e *GenError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 取出第一个匹配的表错误
+func (e *GenError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// JobError 配置文件 gen 中单个任务失败的错误
+type JobError struct {
+	Job string
+	Err error
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job %s: %v", e.Job, e.Err)
+}
+
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// JobsError 汇总所有任务的错误
+type JobsError struct {
+	Errors []*JobError
+}
+
+func (e *JobsError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d jobs failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Is 任意一个任务的错误匹配即可
+func (e *JobsError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 取出第一个匹配的任务错误
+func (e *JobsError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gogf/gf/database/gdb"
+)
+
+// Index 表的索引信息，模板中可以通过 .Indexes 使用
+type Index struct {
+	Name    string   // 索引名，sqlite 自动创建的唯一索引为空
+	Primary bool     // 是否主键
+	Unique  bool     // 是否唯一索引
+	Columns []string // 按索引中的顺序排列的字段
+}
+
+// mysql 的索引，SHOW INDEX 只能逐个表查询，主键排在最前面
+const mysqlIndexesSelect = `
+SELECT TABLE_NAME AS table_name,
+       INDEX_NAME AS index_name,
+       NON_UNIQUE = 0 AS is_unique,
+       INDEX_NAME = 'PRIMARY' AS is_primary,
+       COLUMN_NAME AS column_name
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = DATABASE()`
+
+const mysqlTableIndexesSql = mysqlIndexesSelect + `
+  AND TABLE_NAME = ?
+ORDER BY is_primary DESC, INDEX_NAME, SEQ_IN_INDEX`
+
+// 当前库中所有表的索引，和字段一起预先读取
+const mysqlIndexesSql = mysqlIndexesSelect + `
+ORDER BY TABLE_NAME, is_primary DESC, INDEX_NAME, SEQ_IN_INDEX`
+
+const pgIndexesSelect = `
+SELECT t.relname AS table_name,
+       i.relname AS index_name,
+       ix.indisunique AS is_unique,
+       ix.indisprimary AS is_primary,
+       a.attname AS column_name
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_namespace ns ON ns.oid = t.relnamespace
+CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, seq)
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE ns.nspname = current_schema()`
+
+const pgTableIndexesSql = pgIndexesSelect + `
+  AND t.relname = ?
+ORDER BY i.relname, k.seq`
+
+// 当前 schema 中所有表的索引
+const pgIndexesSql = pgIndexesSelect + `
+ORDER BY t.relname, i.relname, k.seq`
+
+// 获取表的索引
+func tableIndexes(ctx context.Context, db gdb.DB, dialect, table string) ([]*Index, error) {
+	var (
+		result gdb.Result
+		err    error
+	)
+	switch dialect {
+	case DialectPostgres:
+		result, err = db.Ctx(ctx).GetAll(pgTableIndexesSql, table)
+	case DialectSqlite:
+		return sqliteTableIndexes(ctx, db, table)
+	default:
+		result, err = db.Ctx(ctx).GetAll(mysqlTableIndexesSql, table)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return groupTableIndexes(result)[table], nil
+}
+
+// 按表名、索引名分组，结果已经按表名、索引中字段的顺序排好序了
+func groupTableIndexes(result gdb.Result) map[string][]*Index {
+	var (
+		tables = make(map[string][]*Index)
+		index  = make(map[[2]string]*Index)
+	)
+	for _, m := range result {
+		table, name := m["table_name"].String(), m["index_name"].String()
+		idx, ok := index[[2]string{table, name}]
+		if !ok {
+			idx = &Index{
+				Name:    name,
+				Primary: m["is_primary"].Bool(),
+				Unique:  m["is_unique"].Bool(),
+			}
+			index[[2]string{table, name}] = idx
+			tables[table] = append(tables[table], idx)
+		}
+		idx.Columns = append(idx.Columns, m["column_name"].String())
+	}
+	return tables
+}
+
+func sqliteTableIndexes(ctx context.Context, db gdb.DB, table string) ([]*Index, error) {
+	result, err := db.Ctx(ctx).GetAll(fmt.Sprintf("PRAGMA index_list(%s)", sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]*Index, 0, len(result))
+	for _, m := range result {
+		columns, err := db.Ctx(ctx).GetAll(fmt.Sprintf("PRAGMA index_info(%s)", sqliteQuote(m["name"].String())))
+		if err != nil {
+			return nil, err
+		}
+		idx := &Index{
+			Name:    m["name"].String(),
+			Primary: m["origin"].String() == "pk",
+			Unique:  m["unique"].Int() == 1,
+		}
+		// UNIQUE 约束自动创建的索引，名字没有意义
+		if m["origin"].String() == "u" {
+			idx.Name = ""
+		}
+		sort.Slice(columns, func(i, j int) bool {
+			return columns[i]["seqno"].Int() < columns[j]["seqno"].Int()
+		})
+		for _, column := range columns {
+			idx.Columns = append(idx.Columns, column["name"].String())
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes, nil
+}
+
+// 根据索引把 PRI/UNI/MUL 标记到字段上，和 mysql 的 SHOW COLUMNS 保持一致
+func markIndexKeys(fields map[string]*gdb.TableField, indexes []*Index) {
+	for _, idx := range indexes {
+		if len(idx.Columns) == 0 {
+			continue
+		}
+		if idx.Primary {
+			for _, column := range idx.Columns {
+				if field, ok := fields[column]; ok {
+					field.Key = "PRI"
+					field.Null = false
+				}
+			}
+			continue
+		}
+		field, ok := fields[idx.Columns[0]]
+		if !ok {
+			continue
+		}
+		key := "MUL"
+		if idx.Unique && len(idx.Columns) == 1 {
+			key = "UNI"
+		}
+		switch {
+		case field.Key == "":
+			field.Key = key
+		case field.Key == "MUL" && key == "UNI":
+			field.Key = key
+		}
+	}
+}
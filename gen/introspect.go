@@ -0,0 +1,137 @@
+package gen
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/database/gdb"
+)
+
+// mysql 当前库中所有表的字段信息，和 SHOW FULL COLUMNS 的结果一致
+const mysqlColumnsSql = `
+SELECT TABLE_NAME AS table_name,
+       COLUMN_NAME AS field,
+       COLUMN_TYPE AS type,
+       IS_NULLABLE AS is_null,
+       COLUMN_KEY AS ` + "`key`" + `,
+       COLUMN_DEFAULT AS default_value,
+       EXTRA AS extra,
+       COLUMN_COMMENT AS comment
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = DATABASE()
+ORDER BY TABLE_NAME, ORDINAL_POSITION`
+
+// 一次读取整个库的字段信息，代替逐个表查询
+type prefetcher interface {
+	prefetch(ctx context.Context) error
+}
+
+// 一次查询读取所有表的字段和索引信息，sqlite 是本地文件，逐个表查询就够了
+func (s *dbSource) prefetch(ctx context.Context) error {
+	var (
+		columnsSql, indexesSql string
+		toField                func(index int, m gdb.Record) *gdb.TableField
+	)
+	switch s.dialect {
+	case DialectMysql:
+		columnsSql, indexesSql, toField = mysqlColumnsSql, mysqlIndexesSql, mysqlTableField
+	case DialectPostgres:
+		columnsSql, indexesSql, toField = pgColumnsSql, pgIndexesSql, pgTableField
+	default:
+		return nil
+	}
+	result, err := s.db.Ctx(ctx).GetAll(columnsSql)
+	if err != nil {
+		return err
+	}
+	s.fields = groupTableFields(result, toField)
+	if result, err = s.db.Ctx(ctx).GetAll(indexesSql); err != nil {
+		return err
+	}
+	s.indexes = groupTableIndexes(result)
+	return nil
+}
+
+// 按表名分组，结果已经按表名、字段顺序排好序了
+func groupTableFields(result gdb.Result, toField func(index int, m gdb.Record) *gdb.TableField) map[string]map[string]*gdb.TableField {
+	tables := make(map[string]map[string]*gdb.TableField)
+	for _, m := range result {
+		table := m["table_name"].String()
+		fields, ok := tables[table]
+		if !ok {
+			fields = make(map[string]*gdb.TableField)
+			tables[table] = fields
+		}
+		field := toField(len(fields), m)
+		fields[field.Name] = field
+	}
+	return tables
+}
+
+// information_schema.COLUMNS 的一行转成字段信息
+func mysqlTableField(index int, m gdb.Record) *gdb.TableField {
+	return &gdb.TableField{
+		Index:   index,
+		Name:    m["field"].String(),
+		Type:    m["type"].String(),
+		Null:    strings.EqualFold(m["is_null"].String(), "YES"),
+		Key:     m["key"].String(),
+		Default: m["default_value"].Val(),
+		Extra:   m["extra"].String(),
+		Comment: m["comment"].String(),
+	}
+}
+
+// 并发数，默认为 cpu 的核数
+func (o *ModelOptions) jobs() int {
+	if o.Jobs > 0 {
+		return o.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// 单个表读取、生成的结果
+type tableResult struct {
+	files []*genFile
+	op    string
+	err   error
+}
+
+// 用固定数量的 worker 并发读取表结构、生成代码，结果按 tables 的顺序返回
+func genTables(ctx context.Context, src schemaSource, tables []string, jobs int, gen func(meta *tableMeta) ([]*genFile, error)) []*tableResult {
+	results := make([]*tableResult, len(tables))
+	if jobs > len(tables) {
+		jobs = len(tables)
+	}
+	var (
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+	)
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				meta, err := src.Table(ctx, tables[index])
+				if err != nil {
+					results[index] = &tableResult{op: OpIntrospect, err: err}
+					continue
+				}
+				files, err := gen(meta)
+				if err != nil {
+					results[index] = &tableResult{op: OpGenerate, err: err}
+					continue
+				}
+				results[index] = &tableResult{files: files}
+			}
+		}()
+	}
+	for i := range tables {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
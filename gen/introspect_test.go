@@ -0,0 +1,101 @@
+package gen
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/container/gvar"
+	"github.com/gogf/gf/database/gdb"
+)
+
+func TestGroupTableFields(t *testing.T) {
+	record := func(table, field, isNull string, defaultValue interface{}) gdb.Record {
+		return gdb.Record{
+			"table_name":    gvar.New(table),
+			"field":         gvar.New(field),
+			"type":          gvar.New("int"),
+			"is_null":       gvar.New(isNull),
+			"key":           gvar.New(""),
+			"default_value": gvar.New(defaultValue),
+			"extra":         gvar.New(""),
+			"comment":       gvar.New(""),
+		}
+	}
+	tables := groupTableFields(gdb.Result{
+		record("order", "id", "NO", nil),
+		record("order", "amount", "YES", "0"),
+		record("user", "id", "NO", nil),
+	}, mysqlTableField)
+	if len(tables) != 2 || len(tables["order"]) != 2 || len(tables["user"]) != 1 {
+		t.Fatalf("unexpected tables: %+v", tables)
+	}
+	amount := tables["order"]["amount"]
+	if amount.Index != 1 || !amount.Null || amount.Default != "0" {
+		t.Errorf("unexpected amount field: %+v", amount)
+	}
+	// 每个表的字段顺序单独计算
+	if id := tables["user"]["id"]; id.Index != 0 || id.Null || id.Default != nil {
+		t.Errorf("unexpected user id field: %+v", id)
+	}
+}
+
+func TestGroupTableIndexes(t *testing.T) {
+	record := func(table, index string, unique, primary interface{}, column string) gdb.Record {
+		return gdb.Record{
+			"table_name":  gvar.New(table),
+			"index_name":  gvar.New(index),
+			"is_unique":   gvar.New(unique),
+			"is_primary":  gvar.New(primary),
+			"column_name": gvar.New(column),
+		}
+	}
+	// mysql 返回 0、1，postgres 返回 bool
+	tables := groupTableIndexes(gdb.Result{
+		record("order", "PRIMARY", 1, 1, "id"),
+		record("order", "idx_user", 0, 0, "user_id"),
+		record("order", "idx_user", 0, 0, "created_at"),
+		record("user", "PRIMARY", 1, 1, "id"),
+		record("user_role", "user_role_pkey", true, true, "user_id"),
+		record("user_role", "user_role_pkey", true, true, "role_id"),
+	})
+	if len(tables) != 3 || len(tables["order"]) != 2 || len(tables["user"]) != 1 {
+		t.Fatalf("unexpected tables: %+v", tables)
+	}
+	if idx := tables["order"][1]; idx.Name != "idx_user" || idx.Unique || idx.Primary || strings.Join(idx.Columns, ",") != "user_id,created_at" {
+		t.Errorf("unexpected order index: %+v", idx)
+	}
+	if idx := tables["user_role"][0]; !idx.Primary || !idx.Unique || strings.Join(idx.Columns, ",") != "user_id,role_id" {
+		t.Errorf("unexpected user_role index: %+v", idx)
+	}
+}
+
+func TestGenModelJobs(t *testing.T) {
+	var (
+		summaries []string
+		contents  []string
+	)
+	for _, jobs := range []int{1, 8} {
+		genPath := filepath.Join(t.TempDir(), "dao")
+		summary, err := GenModel(context.Background(), ModelOptions{DDL: "testdata/schema.sql", Path: genPath, Jobs: jobs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, path := range summary.Created {
+			names = append(names, filepath.Base(path))
+		}
+		summaries = append(summaries, strings.Join(names, " "))
+		content, _ := ioutil.ReadFile(filepath.Join(genPath, "user_info_gen.go"))
+		contents = append(contents, string(content))
+	}
+	// 并发生成时文件按表的顺序写入，内容和逐个生成一致
+	if summaries[0] != summaries[1] {
+		t.Errorf("created files differ:\n%s\n%s", summaries[0], summaries[1])
+	}
+	if contents[0] == "" || contents[0] != contents[1] {
+		t.Errorf("generated content differs")
+	}
+}
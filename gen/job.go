@@ -0,0 +1,127 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// GenJob 配置文件 gen 中的一个生成任务，没有配置的选项使用命令行的
+type GenJob struct {
+	Name        string   `yaml:"name"`        // 任务名，默认为 key
+	Key         string   `yaml:"key"`         // 配置文件 mysql 中数据库的 key
+	DSN         string   `yaml:"dsn"`         // 数据库连接，优先于 key
+	DDL         string   `yaml:"ddl"`         // CREATE TABLE 的 ddl 文件，设置后不连接数据库
+	Tables      []string `yaml:"tables"`      // 生成的表，支持通配符，为空时生成所有的表
+	Exclude     []string `yaml:"exclude"`     // 排除的表，支持通配符
+	StripPrefix []string `yaml:"stripPrefix"` // 生成结构体和文件名时去掉的表名前缀
+	Path        string   `yaml:"path"`        // 生成的路径
+	ModelPath   string   `yaml:"modelPath"`   // model 的路径，优先于 layout
+	DaoPath     string   `yaml:"daoPath"`     // dao 的路径
+	Layout      string   `yaml:"layout"`      // 目录结构，为空时设置了 path 按 single 生成
+	Package     string   `yaml:"package"`     // model 的包名，默认是路径的最后一级
+	DaoPackage  string   `yaml:"daoPackage"`  // dao 的包名，默认是路径的最后一级
+}
+
+func (j *GenJob) name() string {
+	if j.Name != "" {
+		return j.Name
+	}
+	return j.Key
+}
+
+// 任务的选项，表、路径、包名只使用任务中的，不继承命令行的
+func (j *GenJob) options(opts ModelOptions) ModelOptions {
+	if j.Key != "" {
+		opts.Key = j.Key
+	}
+	opts.DSN = j.DSN
+	opts.DDL = j.DDL
+	opts.Tables = j.Tables
+	opts.Exclude = j.Exclude
+	if len(j.StripPrefix) > 0 {
+		opts.StripPrefix = j.StripPrefix
+	}
+	opts.Path = j.Path
+	opts.ModelPath = j.ModelPath
+	opts.DaoPath = j.DaoPath
+	opts.Layout = j.Layout
+	if opts.Layout == "" && j.Path != "" {
+		opts.Layout = LayoutSingle
+	}
+	opts.Package = j.Package
+	opts.DaoPackage = j.DaoPackage
+	return opts
+}
+
+// JobResult 单个任务的生成结果
+type JobResult struct {
+	Name    string
+	Summary *Summary
+	Err     error
+}
+
+// GenJobs 按配置文件 gen 中的任务依次生成，一个任务失败不影响其他任务
+func GenJobs(ctx context.Context, opts ModelOptions) ([]*JobResult, error) {
+	config, err := getModelConfig(opts.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Gen) == 0 {
+		return nil, fmt.Errorf("no gen jobs in %s", opts.ConfigPath)
+	}
+	jobOpts, err := jobOptions(config, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results = make([]*JobResult, 0, len(config.Gen))
+		jobErr  = &JobsError{}
+	)
+	for i, job := range config.Gen {
+		summary, err := GenModel(ctx, jobOpts[i])
+		results = append(results, &JobResult{Name: job.name(), Summary: summary, Err: err})
+		if err != nil {
+			jobErr.Errors = append(jobErr.Errors, &JobError{Job: job.name(), Err: err})
+		}
+	}
+	if len(jobErr.Errors) > 0 {
+		return results, jobErr
+	}
+	return results, nil
+}
+
+// 每个任务的选项，不同的任务不能生成到同一个目录，否则包会混在一起
+// 路径在这里确定后再传给 GenModel，检查的目录和实际生成的一致
+func jobOptions(config *ModelConfig, opts ModelOptions) ([]ModelOptions, error) {
+	var (
+		jobOpts = make([]ModelOptions, 0, len(config.Gen))
+		paths   = make(map[string]string)
+	)
+	for i, job := range config.Gen {
+		if job.name() == "" {
+			job.Name = fmt.Sprintf("gen[%d]", i)
+		}
+		if job.Path == "" && job.ModelPath == "" && job.Layout == "" {
+			return nil, fmt.Errorf("job %s: path is required", job.name())
+		}
+		o := job.options(opts)
+		if err := resolvePaths(&o, config.Layout); err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.name(), err)
+		}
+		dirs := []string{o.ModelPath}
+		if o.splitDao() {
+			dirs = append(dirs, o.DaoPath)
+		}
+		for _, dir := range dirs {
+			abs, _ := filepath.Abs(dir)
+			if other, ok := paths[abs]; ok {
+				return nil, fmt.Errorf("jobs %s and %s gen to the same path: %s", other, job.name(), dir)
+			}
+			paths[abs] = job.name()
+		}
+		jobOpts = append(jobOpts, o)
+	}
+	return jobOpts, nil
+}
@@ -0,0 +1,89 @@
+package gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenJobs(t *testing.T) {
+	dir := t.TempDir()
+	ddl, _ := filepath.Abs("testdata/schema.sql")
+	userPath := filepath.Join(dir, "user", "model")
+	orgPath := filepath.Join(dir, "org", "dao")
+	config := fmt.Sprintf(`gen:
+  - name: user
+    ddl: %s
+    tables: [user_info]
+    path: %s
+    package: usermodel
+  - name: org
+    ddl: %s
+    tables: [org, not_exist]
+    path: %s
+`, ddl, userPath, ddl, orgPath)
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := GenJobs(context.Background(), ModelOptions{ConfigPath: configPath, Path: filepath.Join(dir, "cli")})
+	// 一个任务中的表失败不影响其他任务
+	var jobErr *JobError
+	if !errors.As(err, &jobErr) || jobErr.Job != "org" || !errors.Is(err, ErrTableNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Err != nil || len(results[0].Summary.Created) != 3 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	for path, want := range map[string]string{
+		filepath.Join(userPath, "user_info_gen.go"): "package usermodel",
+		filepath.Join(orgPath, "org_gen.go"):        "package dao",
+	} {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("%s missing %q", path, want)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(userPath, "org*")); len(files) > 0 {
+		t.Errorf("tables of other jobs should not be generated: %v", files)
+	}
+}
+
+func TestJobOptions(t *testing.T) {
+	config := &ModelConfig{Gen: []*GenJob{
+		{Key: "user", Path: "internal/user/model"},
+		{Key: "order", Layout: LayoutProject},
+	}}
+	opts, err := jobOptions(config, ModelOptions{Key: "default", Tables: []string{"cli_table"}, Path: "repository/dao"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts[0].Key != "user" || opts[0].Layout != LayoutSingle || opts[0].Tables != nil {
+		t.Errorf("unexpected options: %+v", opts[0])
+	}
+	if opts[1].ModelPath != "repository/db/model" || opts[1].DaoPath != "repository/db/dao" || opts[1].Layout != LayoutProject {
+		t.Errorf("unexpected options: %+v", opts[1])
+	}
+
+	// 只指定 model 的路径时，dao 和 GenModel 一样生成到默认的路径
+	opts, err = jobOptions(&ModelConfig{Gen: []*GenJob{{Key: "user", ModelPath: "internal/user/model"}, {Key: "order", Path: "_output/model"}}}, ModelOptions{})
+	if err == nil || !strings.Contains(err.Error(), "user and order") {
+		t.Errorf("expected same path error, got %v %+v", err, opts)
+	}
+
+	config.Gen = append(config.Gen, &GenJob{Key: "pay", Path: "repository/db/dao"})
+	if _, err = jobOptions(config, ModelOptions{}); err == nil || !strings.Contains(err.Error(), "order and pay") {
+		t.Errorf("expected same path error, got %v", err)
+	}
+	if _, err = jobOptions(&ModelConfig{Gen: []*GenJob{{Key: "user"}}}, ModelOptions{}); err == nil {
+		t.Error("expected path required error")
+	}
+}
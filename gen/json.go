@@ -0,0 +1,73 @@
+package gen
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// JSONType json 字段自定义的类型，生成 sql.Scanner 和 driver.Valuer，
+// 其他包中的类型不能定义方法，生成同名的结构体嵌入原类型
+type JSONType struct {
+	Name       string // 字段使用的类型名，如 OrderExtra
+	Embed      string // 嵌入的其他包中的类型，如 x.OrderExtra，同包的类型为空
+	ImportPath string // 其他包的路径，如 github.com/acme/x
+}
+
+// 可以生成方法的类型，同包的类型或者其他包中的类型，如 OrderExtra、x.OrderExtra
+var jsonTypeRegex = regexp.MustCompile(`^(\w+\.)?[A-Za-z_]\w*$`)
+
+// 这些包中的类型本身已经实现了 Scanner 和 Valuer
+var jsonTypeSkipPackages = g.SliceStr{"gorm.io/datatypes", "encoding/json", "database/sql"}
+
+// 是否是 json 类型的字段
+func isJSONColumn(dbType string) bool {
+	switch gstr.ToLower(baseTypeName(dbType)) {
+	case "json", "jsonb":
+		return true
+	}
+	return false
+}
+
+// json 字段在 types 中配置的类型，返回字段使用的类型和需要生成方法的类型，不需要生成时返回 nil
+func genJSONType(typeName, importPath string) (string, *JSONType) {
+	pointer := gstr.HasPrefix(typeName, "*")
+	name := gstr.TrimLeftStr(typeName, "*")
+	if !jsonTypeRegex.MatchString(name) || isBuiltinType(name) || gstr.InArray(jsonTypeSkipPackages, importPath) {
+		return typeName, nil
+	}
+	t := &JSONType{Name: name}
+	if i := strings.Index(name, "."); i >= 0 {
+		t.Name, t.Embed, t.ImportPath = name[i+1:], name, importPath
+	}
+	if pointer {
+		return "*" + t.Name, t
+	}
+	return t.Name, t
+}
+
+func isBuiltinType(name string) bool {
+	switch name {
+	case "string", "bool", "byte", "rune", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "interface":
+		return true
+	}
+	return false
+}
+
+// 生成 json 类型的方法的文件，如 order_extra_json.go
+func genJSONTypeFile(t *JSONType, templates []*fileTemplate, opt *ModelOptions) (*genFile, error) {
+	tpl, err := findTemplate(templates, jsonTemplate)
+	if err != nil {
+		return nil, err
+	}
+	path := gfile.Join(opt.modelPath(), gstr.CaseSnake(t.Name)+"_json.go")
+	content, err := tpl.render(path, map[string]interface{}{"Package": opt.Package, "Type": t})
+	if err != nil {
+		return nil, err
+	}
+	return &genFile{path: path, content: content, mode: writeGenerated, shared: true}, nil
+}
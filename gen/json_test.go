@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/os/gfile"
+)
+
+func TestGenJSONType(t *testing.T) {
+	cases := []struct {
+		typeName   string
+		importPath string
+		want       string
+		generate   bool
+		embed      string
+	}{
+		{"OrderExtra", "", "OrderExtra", true, ""},
+		{"*OrderExtra", "", "*OrderExtra", true, ""},
+		{"x.OrderExtra", "github.com/acme/x", "OrderExtra", true, "x.OrderExtra"},
+		{"datatypes.JSONMap", "gorm.io/datatypes", "datatypes.JSONMap", false, ""},
+		{"json.RawMessage", "encoding/json", "json.RawMessage", false, ""},
+		{"string", "", "string", false, ""},
+		{"[]string", "", "[]string", false, ""},
+		{"map[string]interface{}", "", "map[string]interface{}", false, ""},
+	}
+	for _, c := range cases {
+		got, jsonType := genJSONType(c.typeName, c.importPath)
+		if got != c.want {
+			t.Errorf("%s: type = %s, want %s", c.typeName, got, c.want)
+		}
+		if (jsonType != nil) != c.generate {
+			t.Errorf("%s: unexpected json type %+v", c.typeName, jsonType)
+		}
+		if jsonType != nil && jsonType.Embed != c.embed {
+			t.Errorf("%s: embed = %s, want %s", c.typeName, jsonType.Embed, c.embed)
+		}
+	}
+}
+
+func TestGenStructFieldJSON(t *testing.T) {
+	types := map[string]string{"order.extra": "github.com/acme/x.OrderExtra", "order.tags": "[]string"}
+	cases := []struct {
+		dialect  string
+		field    *gdb.TableField
+		typeName string
+	}{
+		{DialectMysql, &gdb.TableField{Name: "detail", Type: "json"}, "datatypes.JSON"},
+		{DialectMysql, &gdb.TableField{Name: "detail", Type: "json", Null: true}, "datatypes.JSON"},
+		{DialectMysql, &gdb.TableField{Name: "extra", Type: "json", Null: true}, "*OrderExtra"},
+		{DialectMysql, &gdb.TableField{Name: "tags", Type: "json"}, "[]string"},
+		{DialectPostgres, &gdb.TableField{Name: "detail", Type: "jsonb", Null: true}, "datatypes.JSON"},
+		{DialectPostgres, &gdb.TableField{Name: "extra", Type: "jsonb", Null: true}, "*OrderExtra"},
+		{DialectPostgres, &gdb.TableField{Name: "tags", Type: "json"}, "[]string"},
+	}
+	for _, c := range cases {
+		opt := &ModelOptions{Dialect: c.dialect, Nullable: NullablePointer, Types: types}
+		column := genColumn(&tableMeta{Name: "order"}, c.field, opt)
+		if column.GoType != c.typeName {
+			t.Errorf("%s: type = %s, want %s", c.field.Name, column.GoType, c.typeName)
+		}
+	}
+
+	templates, err := loadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := genJSONTypeFile(&JSONType{Name: "OrderExtra", Embed: "x.OrderExtra", ImportPath: "github.com/acme/x"}, templates, &ModelOptions{Path: "dao", Package: "dao"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(file.path, "order_extra_json.go") || !file.shared {
+		t.Errorf("unexpected json type file: %s", file.path)
+	}
+	for _, want := range []string{`"github.com/acme/x"`, "x.OrderExtra\n}", "func (e *OrderExtra) Scan(value interface{}) error", "json.Unmarshal(data, &e.OrderExtra)", "func (e OrderExtra) Value() (driver.Value, error)"} {
+		if !strings.Contains(file.content, want) {
+			t.Errorf("json type file missing %q:\n%s", want, file.content)
+		}
+	}
+}
+
+func TestGenModelJSONTypeConflict(t *testing.T) {
+	genPath := filepath.Join(t.TempDir(), "dao")
+	types := map[string]string{"org.settings": "github.com/acme/a.Settings", "role.extra": "github.com/acme/b.Settings"}
+	opts := ModelOptions{DDL: "testdata/*.sql", Path: genPath, Tables: []string{"org", "role"}, Types: types}
+	_, err := GenModel(context.Background(), opts)
+	var tableErr *TableError
+	if !errors.As(err, &tableErr) || tableErr.Table != "role" || !strings.Contains(err.Error(), "settings_json.go conflicts with the one generated for table org") {
+		t.Fatalf("expected json type conflict, got %v", err)
+	}
+	// 冲突的表不写入文件，其他的表正常生成
+	if gfile.Exists(filepath.Join(genPath, "role_gen.go")) || !gfile.Exists(filepath.Join(genPath, "org_gen.go")) {
+		t.Error("only the conflicting table should be skipped")
+	}
+	if content := gfile.GetContents(filepath.Join(genPath, "settings_json.go")); !strings.Contains(content, `"github.com/acme/a"`) {
+		t.Errorf("settings_json.go should be generated for org:\n%s", content)
+	}
+}
@@ -0,0 +1,135 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/text/gregex"
+)
+
+// 生成的目录结构
+const (
+	LayoutSingle  = "single"  // model 和 dao 生成在同一个包中
+	LayoutProject = "project" // 和 fgen project 生成的目录一致，model 在 repository/db/model，dao 在 repository/db/dao
+)
+
+// 目录结构对应的 model、dao 的路径
+var layoutPaths = map[string][2]string{
+	LayoutProject: {"repository/db/model", "repository/db/dao"},
+}
+
+// 确定生成的路径，GenModel 和多个任务检查目录冲突时都用这个，没有指定路径时生成到 _output/model
+func resolvePaths(opts *ModelOptions, configLayout string) error {
+	if opts.Path == "" {
+		opts.Path = "_output/model"
+	}
+	return resolveLayout(opts, configLayout)
+}
+
+// 按目录结构确定 model 和 dao 的路径，单独指定的路径优先
+func resolveLayout(opts *ModelOptions, configLayout string) error {
+	layout := opts.Layout
+	if layout == "" {
+		layout = configLayout
+	}
+	opts.Layout = layout
+	switch layout {
+	case "", LayoutSingle:
+	default:
+		paths, ok := layoutPaths[layout]
+		if !ok {
+			return fmt.Errorf("unsupported layout: %s", layout)
+		}
+		if opts.ModelPath == "" {
+			opts.ModelPath = paths[0]
+		}
+		if opts.DaoPath == "" {
+			opts.DaoPath = paths[1]
+		}
+	}
+	if opts.ModelPath == "" {
+		opts.ModelPath = opts.Path
+	}
+	if opts.DaoPath == "" {
+		opts.DaoPath = opts.Path
+	}
+	return nil
+}
+
+// model 的路径，没有单独指定时和 Path 相同
+func (o *ModelOptions) modelPath() string {
+	if o.ModelPath != "" {
+		return o.ModelPath
+	}
+	return o.Path
+}
+
+// dao 的路径，没有单独指定时和 Path 相同
+func (o *ModelOptions) daoPath() string {
+	if o.DaoPath != "" {
+		return o.DaoPath
+	}
+	return o.Path
+}
+
+// model 和 dao 是否在不同的包中
+func (o *ModelOptions) splitDao() bool {
+	model, _ := filepath.Abs(o.modelPath())
+	dao, _ := filepath.Abs(o.daoPath())
+	return model != dao
+}
+
+// 根据 go.mod 计算目录的导入路径，如 repository/db/model -> github.com/acme/demo/repository/db/model
+func importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		gomod := filepath.Join(root, "go.mod")
+		if gfile.Exists(gomod) {
+			match, _ := gregex.MatchString(`(?m)^module\s+"?([^\s"]+)"?`, gfile.GetContents(gomod))
+			if len(match) < 2 {
+				return "", fmt.Errorf("no module path in %s", gomod)
+			}
+			module := match[1]
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return module, nil
+			}
+			return module + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(root) == root {
+			return "", fmt.Errorf("go.mod not found for %s, the dao package can not import the model package", dir)
+		}
+	}
+}
+
+// 生成 dao 包中的 init.go，只在 model 和 dao 分开生成或 project 目录结构时生成，避免和已有包中的 _db 冲突
+func genDBClientFile(templates []*fileTemplate, opt *ModelOptions) (*genFile, error) {
+	tpl, err := findTemplate(templates, dbClientTemplate)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(opt.daoPath(), "init.go")
+	content, err := tpl.render(path, map[string]interface{}{"Package": opt.daoPackage()})
+	if err != nil {
+		return nil, err
+	}
+	return &genFile{path: path, content: content, mode: writeOnce, shared: true}, nil
+}
+
+// dao 的包名，和 model 在同一个包中时相同
+func (o *ModelOptions) daoPackage() string {
+	switch {
+	case o.DaoPackage != "":
+		return o.DaoPackage
+	case !o.splitDao():
+		return o.Package
+	}
+	return filepath.Base(o.daoPath())
+}
@@ -0,0 +1,69 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveLayout(t *testing.T) {
+	cases := []struct {
+		opts         ModelOptions
+		configLayout string
+		model, dao   string
+	}{
+		{ModelOptions{Path: "repository/dao"}, "", "repository/dao", "repository/dao"},
+		{ModelOptions{Path: "repository/dao"}, LayoutProject, "repository/db/model", "repository/db/dao"},
+		{ModelOptions{Path: "repository/dao", Layout: LayoutSingle}, LayoutProject, "repository/dao", "repository/dao"},
+		{ModelOptions{Path: "repository/dao", Layout: LayoutProject, DaoPath: "internal/dao"}, "", "repository/db/model", "internal/dao"},
+		{ModelOptions{Path: "repository/dao", ModelPath: "internal/model"}, "", "internal/model", "repository/dao"},
+	}
+	for _, c := range cases {
+		opts := c.opts
+		if err := resolveLayout(&opts, c.configLayout); err != nil {
+			t.Fatal(err)
+		}
+		if opts.ModelPath != c.model || opts.DaoPath != c.dao {
+			t.Errorf("%+v %s: paths = %s %s, want %s %s", c.opts, c.configLayout, opts.ModelPath, opts.DaoPath, c.model, c.dao)
+		}
+	}
+	if err := resolveLayout(&ModelOptions{Layout: "flat"}, ""); err == nil {
+		t.Error("expected error for unsupported layout")
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("// demo\nmodule github.com/acme/demo\n\ngo 1.18\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modelPath := filepath.Join(dir, "repository", "db", "model")
+	if err := os.MkdirAll(modelPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		modelPath:                       "github.com/acme/demo/repository/db/model",
+		filepath.Join(dir, "not_exist"): "github.com/acme/demo/not_exist",
+		dir:                             "github.com/acme/demo",
+	} {
+		got, err := importPath(path)
+		if err != nil || got != want {
+			t.Errorf("%s: import path = %s, %v, want %s", path, got, err, want)
+		}
+	}
+
+	data := &TemplateData{Package: "model", ModelImport: "github.com/acme/demo/repository/db/model"}
+	for typeName, want := range map[string]string{
+		"UserInfoModel":   "model.UserInfoModel",
+		"*UserInfoStatus": "*model.UserInfoStatus",
+		"[]UserRoleKey":   "[]model.UserRoleKey",
+		"int64":           "int64",
+		"*time.Time":      "*time.Time",
+		"[]byte":          "[]byte",
+	} {
+		if got := data.Model(typeName); got != want {
+			t.Errorf("Model(%s) = %s, want %s", typeName, got, want)
+		}
+	}
+}
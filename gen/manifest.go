@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gogf/gf/os/gfile"
+	"gopkg.in/yaml.v2"
+)
+
+// ManifestFile 默认的项目清单文件，在执行 fgen model 的目录中查找
+const ManifestFile = "fgen.yaml"
+
+// Manifest 提交到仓库中的 fgen.yaml，保证每个人生成的结果一致，命令行参数优先
+type Manifest struct {
+	Config      string            `yaml:"config"`      // 数据库配置文件的路径
+	Key         string            `yaml:"key"`         // 配置文件中数据库的 key
+	DSN         string            `yaml:"dsn"`         // 数据库连接，支持 ${DB_DSN} 环境变量，避免提交密码
+	DDL         string            `yaml:"ddl"`         // CREATE TABLE 的 ddl 文件，设置后不连接数据库
+	Tables      []string          `yaml:"tables"`      // 生成的表，支持通配符，为空时生成所有的表
+	Exclude     []string          `yaml:"exclude"`     // 排除的表，支持通配符
+	StripPrefix []string          `yaml:"stripPrefix"` // 生成结构体和文件名时去掉的表名前缀
+	Path        string            `yaml:"path"`        // 生成的路径
+	ModelPath   string            `yaml:"modelPath"`   // model 的路径
+	DaoPath     string            `yaml:"daoPath"`     // dao 的路径
+	Layout      string            `yaml:"layout"`      // 目录结构，为空时设置了 path 按 single 生成
+	Package     string            `yaml:"package"`     // model 的包名
+	DaoPackage  string            `yaml:"daoPackage"`  // dao 的包名
+	Nullable    string            `yaml:"nullable"`    // 可空字段的类型策略
+	Tags        []string          `yaml:"tags"`        // 额外生成的标签，如 json:camel、form
+	TagStyle    string            `yaml:"tagStyle"`    // json/form 标签默认的命名风格
+	Types       map[string]string `yaml:"types"`       // 自定义的类型映射，优先于配置文件
+	Conventions *Conventions      `yaml:"conventions"` // 软删除、自动时间的字段约定
+	TemplateDir string            `yaml:"templateDir"` // 自定义模板的目录
+	Routines    bool              `yaml:"routines"`    // 生成调用存储过程和函数的 RoutineDao
+}
+
+// LoadManifest 读取 fgen.yaml，文件不存在时返回 nil，清单中的相对路径转成相对于清单所在目录的路径
+func LoadManifest(path string) (*Manifest, error) {
+	if !gfile.Exists(path) {
+		return nil, nil
+	}
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err = yaml.UnmarshalStrict(file, &manifest); err != nil {
+		return nil, fmt.Errorf("parse %s failed: %w", path, err)
+	}
+	manifest.DSN = os.ExpandEnv(manifest.DSN)
+	// 清单中的路径相对于清单所在的目录，-manifest sub/fgen.yaml 时也能找到
+	dir := filepath.Dir(path)
+	for _, p := range []*string{&manifest.Config, &manifest.DDL, &manifest.Path, &manifest.ModelPath, &manifest.DaoPath, &manifest.TemplateDir} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	// 和配置文件中的一样，没有设置的约定使用默认的
+	if manifest.Conventions != nil {
+		manifest.Conventions = mergeConventions(manifest.Conventions)
+	}
+	return &manifest, nil
+}
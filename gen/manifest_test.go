@@ -0,0 +1,55 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	if m, err := LoadManifest(filepath.Join(dir, ManifestFile)); m != nil || err != nil {
+		t.Fatalf("missing manifest should be ignored: %+v %v", m, err)
+	}
+
+	path := filepath.Join(dir, ManifestFile)
+	content := `dsn: ${FGEN_TEST_DSN}
+tables: [user_info, org]
+path: internal/model
+tags: [json:camel, form]
+types:
+  decimal: github.com/shopspring/decimal.Decimal
+conventions:
+  timeUnit: milli
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("FGEN_TEST_DSN", "root:root@tcp(127.0.0.1:3306)/demo")
+	defer os.Unsetenv("FGEN_TEST_DSN")
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.DSN != "root:root@tcp(127.0.0.1:3306)/demo" || strings.Join(m.Tables, ",") != "user_info,org" || len(m.Tags) != 2 {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	// 路径相对于清单所在的目录
+	if m.Path != filepath.Join(dir, "internal/model") || m.DDL != "" {
+		t.Errorf("unexpected paths: %+v", m)
+	}
+	// 没有设置的约定使用默认的
+	if c := m.Conventions; c.TimeUnit != TimeUnitMilli || strings.Join(c.SoftDelete, ",") != "deleted_at" {
+		t.Errorf("unexpected conventions: %+v", c)
+	}
+
+	// 写错的字段直接报错，避免提交的清单不生效
+	if err = ioutil.WriteFile(path, []byte("table: [user_info]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadManifest(path); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
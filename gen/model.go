@@ -0,0 +1,729 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/frame/g"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/os/glog"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/gogf/gf/util/gconv"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	DialectMysql    = "mysql"
+	DialectPostgres = "pgsql"
+	DialectSqlite   = "sqlite"
+)
+
+// 可空字段的类型策略
+const (
+	NullablePointer = "pointer" // *string、*time.Time
+	NullableSql     = "sql"     // sql.NullString、sql.NullTime
+	NullableNull    = "null"    // gopkg.in/guregu/null.v4 的 null.String、null.Time
+	NullableNone    = "none"    // 不处理，和非空字段一样
+)
+
+// 文件已存在时的处理方式
+const (
+	OverwriteAsk    = ""       // 询问，非终端时跳过
+	OverwriteForce  = "force"  // 直接覆盖
+	OverwriteSkip   = "skip"   // 跳过
+	OverwriteBackup = "backup" // 备份成 .bak 后覆盖
+)
+
+// 文件的生成结果
+const (
+	fileCreated     = "created"
+	fileOverwritten = "overwritten"
+	fileBackedUp    = "backed-up"
+	fileSkipped     = "skipped"
+	fileUnchanged   = "unchanged" // 内容没有变化或者自定义方法的文件已存在，不写入
+)
+
+// ModelOptions 生成 model 的选项
+type ModelOptions struct {
+	DSN         string            // 数据库连接，为空时从配置文件中读取
+	ConfigPath  string            // 配置文件的路径，用于读取数据库连接和 types
+	Key         string            // 配置文件中数据库的 key
+	DDL         string            // CREATE TABLE 的 ddl 文件，支持通配符，设置后不连接数据库
+	Path        string            // 生成的路径，默认 _output/model，没有单独指定 model、dao 的路径时都生成在这里
+	ModelPath   string            // model 的路径，优先于 Layout
+	DaoPath     string            // dao 的路径，和 model 不在同一个包时根据 go.mod 导入 model 包
+	Layout      string            // 目录结构，single 或 project，为空时使用配置文件中的 layout
+	Package     string            // model 的包名，默认是路径的最后一级
+	DaoPackage  string            // dao 的包名，默认是路径的最后一级
+	Tables      []string          // 生成的表，支持通配符，为空时生成所有的表
+	Exclude     []string          // 排除的表，支持通配符
+	StripPrefix []string          // 生成结构体和文件名时去掉的表名前缀
+	Dialect     string            // 数据库类型，由 dsn 或配置文件决定
+	Nullable    string            // 可空字段的类型策略，默认 pointer
+	Types       map[string]string // 自定义的类型映射，key 为数据库类型或 table.column，优先于配置文件
+	Tags        []StructTag       // 额外生成的标签，如 json、form、xlsx、validate
+	Conventions *Conventions      // 软删除、自动时间的字段约定，为空时使用配置文件中的或默认的约定
+	TemplateDir string            // 自定义模板的目录，同名的模板覆盖内置的 model.go.tmpl，其他的模板额外生成文件
+	Overwrite   string            // 文件已存在时的处理方式，默认询问
+	DryRun      bool              // 只输出会生成的文件，不写入
+	Diff        bool              // 输出和已有文件的 diff，不写入
+	Check       bool              // 有文件变化时返回 ErrOutOfDate，用于 CI 检查表结构是否和 model 一致
+	Output      io.Writer         // diff 的输出，默认 os.Stdout
+	Logger      *glog.Logger      // 生成过程中的日志，默认 glog 的默认 logger，可以通过 SetWriter 重定向或者关闭
+	Jobs        int               // 并发读取、生成的表数，默认 cpu 的核数
+	Routines    bool              // 生成调用存储过程和函数的 RoutineDao
+
+	modelImport string // dao 中导入 model 包的路径，在同一个包中时为空
+}
+
+// dry-run、diff、check 时不写入文件
+func (o *ModelOptions) preview() bool {
+	return o.DryRun || o.Diff || o.Check
+}
+
+func (o *ModelOptions) logger() *glog.Logger {
+	if o.Logger == nil {
+		return glog.DefaultLogger()
+	}
+	return o.Logger
+}
+
+func (o *ModelOptions) output() io.Writer {
+	if o.Output == nil {
+		return os.Stdout
+	}
+	return o.Output
+}
+
+// 表的元信息
+type tableMeta struct {
+	Name        string
+	Fields      map[string]*gdb.TableField
+	Indexes     []*Index
+	ForeignKeys []*foreignKey // 只有从 ddl 中解析时才有，数据库中的外键通过 schemaSource 一次读取
+	Relations   []*Relation
+	View        bool // 视图，dao 只生成查询方法
+}
+
+// 主键字段，按主键索引中的顺序，没有索引信息时按字段顺序
+func (m *tableMeta) primaryKey() []string {
+	for _, index := range m.Indexes {
+		if index.Primary {
+			return index.Columns
+		}
+	}
+	fields := make([]*gdb.TableField, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		if gstr.ContainsI(field.Key, "pri") {
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Index < fields[j].Index
+	})
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.Name)
+	}
+	return columns
+}
+
+// GenModel 根据数据库或者 ddl 文件生成 model，单个表的错误会汇总到 GenError 中返回
+func GenModel(ctx context.Context, opts ModelOptions) (*Summary, error) {
+	if opts.Nullable == "" {
+		opts.Nullable = NullablePointer
+	}
+
+	config, err := getModelConfig(opts.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if err = resolvePaths(&opts, config.Layout); err != nil {
+		return nil, err
+	}
+	if opts.Package == "" {
+		opts.Package = filepath.Base(opts.ModelPath) // default:db
+	}
+	if opts.splitDao() {
+		if opts.modelImport, err = importPath(opts.ModelPath); err != nil {
+			return nil, err
+		}
+	}
+	opts.Types = mergeTypes(config.Types, opts.Types)
+	if opts.Conventions == nil {
+		opts.Conventions = mergeConventions(config.Conventions)
+	}
+
+	templates, err := loadTemplates(opts.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := openSource(&opts)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close(ctx)
+
+	// 视图和表一样生成，dao 只有查询方法
+	views, err := src.Views(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get views failed: %w", err)
+	}
+	tables, err := selectTables(ctx, src, views, opts.Tables, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkTableNames(tables, &opts); err != nil {
+		return nil, err
+	}
+
+	if !opts.preview() {
+		for _, path := range []string{opts.ModelPath, opts.DaoPath} {
+			if err := gfile.Mkdir(path); err != nil {
+				return nil, fmt.Errorf("mkdir for generating path:%s failed: %w", path, err)
+			}
+		}
+	}
+
+	fks, err := src.ForeignKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get foreign keys failed: %w", err)
+	}
+	relations := genRelations(fks, tables, &opts)
+
+	// 整个库的字段一次读取，失败时退回逐个表查询
+	if p, ok := src.(prefetcher); ok && len(tables) > 1 {
+		if err := p.prefetch(ctx); err != nil {
+			opts.logger().Warningf("prefetch columns and indexes failed, introspect tables one by one: %v", err)
+		}
+	}
+	results := genTables(ctx, src, tables, opts.jobs(), func(meta *tableMeta) ([]*genFile, error) {
+		meta.Relations = relations[meta.Name]
+		if len(meta.primaryKey()) == 0 && !meta.View {
+			opts.logger().Warningf("table %s has no primary key, the primary key methods are not generated", meta.Name)
+		}
+		return genModelFiles(meta, templates, &opts)
+	})
+
+	checkSharedFiles(tables, results)
+
+	// 按表的顺序写入，保证输出稳定，覆盖时的询问也不会交错
+	var (
+		summary = &Summary{}
+		genErr  = &GenError{}
+		shared  = make(map[string]bool) // 已经生成过的多个表共用的文件
+	)
+	write := func(table string, file *genFile) error {
+		if file.shared {
+			if shared[file.path] {
+				return nil
+			}
+			shared[file.path] = true
+		}
+		if opts.preview() {
+			summary.add(file.path, previewGenFile(file, &opts))
+			return nil
+		}
+		status, err := writeGenFile(file, opts.Overwrite, opts.logger())
+		if err != nil {
+			return err
+		}
+		summary.add(file.path, status)
+		return nil
+	}
+	for i, table := range tables {
+		result := results[i]
+		if result.err != nil {
+			genErr.add(table, result.op, result.err)
+			continue
+		}
+		for _, file := range result.files {
+			if err := write(table, file); err != nil {
+				genErr.add(table, OpWrite, err)
+				break
+			}
+		}
+	}
+
+	// 存储过程和函数生成在 dao 目录的 routines_gen.go 中
+	if opts.Routines {
+		if err := genRoutinesFile(ctx, src, templates, &opts, write); err != nil {
+			genErr.add("routines", OpGenerate, err)
+		}
+	}
+	if len(genErr.Errors) > 0 {
+		return summary, genErr
+	}
+	if opts.Check && summary.Changed() > 0 {
+		return summary, fmt.Errorf("%w: %d files", ErrOutOfDate, summary.Changed())
+	}
+	return summary, nil
+}
+
+// 多个表共用的文件内容应该相同，不同时（如不同包中的同名 json 类型都生成 settings_json.go）后面的表报错，不写入文件
+func checkSharedFiles(tables []string, results []*tableResult) {
+	owners := make(map[string]*genFile)
+	ownerTables := make(map[string]string)
+	for i, result := range results {
+		if result.err != nil {
+			continue
+		}
+		for _, file := range result.files {
+			if !file.shared {
+				continue
+			}
+			owner, ok := owners[file.path]
+			if !ok {
+				owners[file.path], ownerTables[file.path] = file, tables[i]
+				continue
+			}
+			if owner.content != file.content {
+				results[i] = &tableResult{op: OpGenerate, err: fmt.Errorf("%s conflicts with the one generated for table %s, types with the same name from different packages can not be used in one package", file.path, ownerTables[file.path])}
+				break
+			}
+		}
+	}
+}
+
+// 配置文件中的类型映射和传入的合并，传入的优先
+func mergeTypes(config, types map[string]string) map[string]string {
+	if len(config) == 0 {
+		return types
+	}
+	merged := make(map[string]string, len(config)+len(types))
+	for k, v := range config {
+		merged[k] = v
+	}
+	for k, v := range types {
+		merged[k] = v
+	}
+	return merged
+}
+
+// 生成结构体对象
+func genStructDefinition(columns []*Column, relations []*Relation, camelName string) string {
+	buffer := bytes.NewBuffer(nil)
+	array := make([][]string, 0, len(columns)+len(relations))
+	for _, column := range columns {
+		array = append(array, columnFields(column))
+	}
+	for _, relation := range relations {
+		array = append(array, relationFields(relation))
+	}
+	tw := tablewriter.NewWriter(buffer)
+	tw.SetBorder(false)
+	tw.SetRowLine(false)
+	tw.SetAutoWrapText(false)
+	tw.SetCenterSeparator("")
+	tw.AppendBulk(array)
+	tw.Render()
+
+	stContent := buffer.String()
+	stContent = gstr.Replace(stContent, " #", "")
+	stContent = gstr.Replace(stContent, " |", "")
+	buffer.Reset()
+	buffer.WriteString("type ")
+	buffer.WriteString(camelName + " struct{\n")
+	buffer.WriteString(stContent)
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+// 按字段顺序生成所有的列
+func genColumns(meta *tableMeta, opt *ModelOptions) []*Column {
+	columns := make([]*Column, len(meta.Fields))
+	for _, field := range meta.Fields {
+		columns[field.Index] = genColumn(meta, field, opt)
+	}
+	return columns
+}
+
+// 生成结构体字段
+func genStructField(meta *tableMeta, field *gdb.TableField, opt *ModelOptions) []string {
+	return columnFields(genColumn(meta, field, opt))
+}
+
+// 结构体字段在 tablewriter 中的一行
+func columnFields(column *Column) []string {
+	as := []string{
+		"   #" + column.GoName,
+		" #" + column.GoType,
+		" #`" + column.Tag + "`",
+	}
+	if column.Comment != "" {
+		as = append(as, " #"+fmt.Sprintf("// %s", column.Comment))
+	}
+	return as
+}
+
+// 根据表字段生成模板中使用的列信息
+func genColumn(meta *tableMeta, field *gdb.TableField, opt *ModelOptions) *Column {
+	var comment string
+	typeName, dbType := columnTypeName(field, opt)
+	override, importPath, overridden := overrideTypeName(meta.Name, field, opt.Types)
+	var jsonType *JSONType
+	if overridden {
+		typeName = override
+		// json 字段自定义的类型生成 Scanner 和 Valuer
+		if isJSONColumn(field.Type) {
+			typeName, jsonType = genJSONType(override, importPath)
+		}
+	}
+	comment = gstr.ReplaceIByArray(field.Comment, g.SliceStr{
+		"\n", "",
+		"\r", "",
+	})
+	comment = gstr.Trim(comment)
+
+	baseType := typeName
+	primaryKey := gstr.ContainsI(field.Key, "pri")
+	typeName, conventionTags := opt.Conventions.apply(field, typeName, opt.logger())
+	// 自动时间和软删除的字段由 gorm 赋值，不需要校验
+	autoValue := len(conventionTags) > 0 || gstr.HasSuffix(typeName, ".DeletedAt")
+	// 自定义了类型或者符合约定的字段不生成枚举
+	var enum *Enum
+	if !overridden && typeName == baseType {
+		enum = genEnum(relationCamelName(meta.Name, opt)+gstr.CaseCamel(field.Name), field.Name, field.Type, typeName, comment, opt.logger())
+	}
+	if enum != nil {
+		typeName, baseType = enum.Name, enum.Name
+	}
+	// 主键不会为 NULL，软删除的类型自己处理 NULL
+	if field.Null && !primaryKey && !gstr.HasSuffix(typeName, ".DeletedAt") {
+		typeName = nullableTypeName(typeName, opt.Nullable)
+	}
+
+	// 标签写在反引号中，内容里的反引号替换成单引号
+	ormTag := gstr.Replace(strings.Join(genGormTags(meta, field, dbType, comment, conventionTags), ";"), "`", "'")
+	tags := append([]string{"gorm:" + strconv.Quote(ormTag)}, genStructTags(field, comment, opt.Tags, autoValue)...)
+
+	column := &Column{
+		Name:          field.Name,
+		GoName:        gstr.CaseCamel(field.Name),
+		GoType:        typeName,
+		BaseType:      baseType,
+		DBType:        dbType,
+		Tag:           strings.Join(tags, " "),
+		Comment:       comment,
+		Nullable:      field.Null && !primaryKey,
+		PrimaryKey:    primaryKey,
+		Unique:        gstr.ContainsI(field.Key, "uni"),
+		AutoIncrement: gstr.ContainsI(field.Extra, "auto_increment"),
+		Enum:          enum,
+		JSONType:      jsonType,
+	}
+	if field.Default != nil {
+		column.HasDefault = true
+		column.Default = gconv.String(field.Default)
+	}
+	return column
+}
+
+// 按数据库类型映射 Go 类型，返回 Go 类型和 gorm 标签中的 type
+func columnTypeName(field *gdb.TableField, opt *ModelOptions) (typeName, dbType string) {
+	t := baseTypeName(field.Type)
+	switch {
+	case opt.Dialect == DialectPostgres:
+		typeName, dbType = pgTypeName(t)
+		// 带长度、精度的类型，如 varchar(64)、numeric(10,2)
+		if gstr.Contains(field.Type, "(") {
+			dbType = field.Type
+		}
+	case opt.Dialect == DialectSqlite:
+		typeName = sqliteTypeName(t)
+		dbType = gstr.ToLower(field.Type)
+	default:
+		typeName = mysqlTypeName(field)
+		dbType = gstr.ToLower(field.Type)
+	}
+	return typeName, dbType
+}
+
+// 生成 gorm 标签，保证 AutoMigrate 能还原出原来的表结构
+func genGormTags(meta *tableMeta, field *gdb.TableField, dbType, comment string, extra []string) []string {
+	tags := []string{"column:" + field.Name}
+	autoIncrement := gstr.ContainsI(field.Extra, "auto_increment")
+	// 自增字段指定了 type 之后 gorm 不会再加上 AUTO_INCREMENT，交给 go 类型推导
+	if dbType != "" && !autoIncrement {
+		tags = append(tags, "type:"+dbType)
+	}
+	if size := typeSize(field.Type); size != "" {
+		tags = append(tags, "size:"+size)
+	}
+	if gstr.ContainsI(field.Key, "pri") {
+		tags = append(tags, "primaryKey")
+		// 联合主键中有 id 字段时 gorm 会把整数类型的 id 当成自增的
+		if !autoIncrement && len(meta.primaryKey()) > 1 {
+			tags = append(tags, "autoIncrement:false")
+		}
+	}
+	if autoIncrement {
+		tags = append(tags, "autoIncrement")
+	}
+	if !field.Null && !gstr.ContainsI(field.Key, "pri") {
+		tags = append(tags, "not null")
+	}
+	if field.Default != nil && !autoIncrement {
+		defaultValue := gconv.String(field.Default)
+		if defaultValue == "" {
+			defaultValue = "''"
+		}
+		tags = append(tags, "default:"+escapeGormTag(defaultValue))
+	}
+	for _, index := range meta.Indexes {
+		if index.Primary {
+			continue
+		}
+		for i, column := range index.Columns {
+			if column != field.Name {
+				continue
+			}
+			key := "index"
+			if index.Unique {
+				key = "uniqueIndex"
+			}
+			name := index.Name
+			// 联合索引需要同一个名字才能归到一起
+			if name == "" && len(index.Columns) > 1 {
+				name = "idx_" + meta.Name + "_" + strings.Join(index.Columns, "_")
+			}
+			tag := key
+			if name != "" {
+				tag += ":" + escapeGormTag(name)
+			}
+			if len(index.Columns) > 1 {
+				tag += fmt.Sprintf(",priority:%d", i+1)
+			}
+			tags = append(tags, tag)
+		}
+	}
+	tags = append(tags, extra...)
+	if comment != "" {
+		tags = append(tags, "comment:"+escapeGormTag(comment))
+	}
+	return tags
+}
+
+// 字符串类型的长度，如 varchar(64) -> 64
+func typeSize(t string) string {
+	match, _ := gregex.MatchString(`^(?i)(var)?(char|binary)\((\d+)\)`, gstr.Trim(t))
+	if len(match) == 4 {
+		return match[3]
+	}
+	return ""
+}
+
+// gorm 标签中的 ; 需要转义
+func escapeGormTag(s string) string {
+	return gstr.Replace(s, ";", "\\;")
+}
+
+// 可空字段的 go 类型
+func nullableTypeName(typeName, nullable string) string {
+	// 切片本身可以为 nil
+	if gstr.HasPrefix(typeName, "[]") || gstr.HasPrefix(typeName, "pq.") || gstr.HasPrefix(typeName, "*") || typeName == "datatypes.JSON" {
+		return typeName
+	}
+	switch nullable {
+	case NullableNone:
+		return typeName
+	case NullableSql:
+		switch typeName {
+		case "string":
+			return "sql.NullString"
+		case "int", "int64", "uint32", "uint16":
+			return "sql.NullInt64"
+		case "int32":
+			return "sql.NullInt32"
+		case "int16", "int8":
+			return "sql.NullInt16"
+		case "uint8":
+			return "sql.NullByte"
+		case "float64", "float32":
+			return "sql.NullFloat64"
+		case "bool":
+			return "sql.NullBool"
+		case "time.Time":
+			return "sql.NullTime"
+		}
+	case NullableNull:
+		switch typeName {
+		case "string":
+			return "null.String"
+		case "int", "int64", "int32", "int16", "int8", "uint32", "uint16", "uint8":
+			return "null.Int"
+		case "float64", "float32":
+			return "null.Float"
+		case "bool":
+			return "null.Bool"
+		case "time.Time":
+			return "null.Time"
+		}
+	}
+	// 没有对应类型的都用指针
+	return "*" + typeName
+}
+
+// 表对应的文件名，去掉了表名前缀
+func tableFileName(table string, opt *ModelOptions) string {
+	return gstr.Trim(gstr.CaseSnake(stripTablePrefix(table, opt.StripPrefix)), "-_.")
+}
+
+// 去掉前缀后不同的表可能重名，如 -strip-prefix user_ 时的 user_role 和 role，生成的结构体和文件会互相覆盖
+func checkTableNames(tables []string, opt *ModelOptions) error {
+	var (
+		types = make(map[string]string, len(tables))
+		files = make(map[string]string, len(tables))
+	)
+	for _, table := range tables {
+		typeName, fileName := relationCamelName(table, opt), tableFileName(table, opt)
+		if other, ok := types[typeName]; ok {
+			return fmt.Errorf("tables %s and %s have the same name %s after stripping prefix", other, table, typeName)
+		}
+		if other, ok := files[fileName]; ok {
+			return fmt.Errorf("tables %s and %s have the same file name %s after stripping prefix", other, table, fileName)
+		}
+		types[typeName], files[fileName] = table, table
+	}
+	return nil
+}
+
+// 根据模板生成一个表的所有文件
+func genModelFiles(meta *tableMeta, templates []*fileTemplate, opt *ModelOptions) ([]*genFile, error) {
+	data := genTemplateData(meta, opt)
+	fileName := tableFileName(meta.Name, opt)
+	files := make([]*genFile, 0, len(templates))
+	for _, t := range templates {
+		if !t.perTable() {
+			continue
+		}
+		path := gfile.Join(t.dir(opt), t.fileName(fileName))
+		content, err := t.render(path, data)
+		if err != nil {
+			return nil, err
+		}
+		file := &genFile{path: path, content: content, mode: t.writeMode(content)}
+		if file.mode == writeOnce {
+			// 旧版本把所有的代码都生成在 user_info.go 中，和 user_info_gen.go 重复
+			file.legacy = "type " + data.ModelName + " struct"
+		}
+		files = append(files, file)
+	}
+	// json 字段自定义的类型每个类型一个文件，多个表用到时内容相同
+	for _, column := range data.Columns {
+		if column.JSONType == nil {
+			continue
+		}
+		file, err := genJSONTypeFile(column.JSONType, templates, opt)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	// dao 共用的数据库连接
+	if !opt.splitDao() && opt.Layout != LayoutProject {
+		return files, nil
+	}
+	file, err := genDBClientFile(templates, opt)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, file), nil
+}
+
+// 模板中使用的数据
+func genTemplateData(meta *tableMeta, opt *ModelOptions) *TemplateData {
+	variable := stripTablePrefix(gstr.TrimLeftStr(meta.Name, ","), opt.StripPrefix)
+	camelName := gstr.CaseCamel(variable)
+	modelName := fmt.Sprintf("%sModel", camelName)
+	columns := genColumns(meta, opt)
+	relations := finishRelations(columns, meta.Relations, opt)
+	structDefine := genStructDefinition(columns, relations, modelName)
+
+	data := &TemplateData{
+		Package:      opt.Package,
+		DaoPackage:   opt.daoPackage(),
+		ModelImport:  opt.modelImport,
+		Dialect:      opt.Dialect,
+		Imports:      genImports(structDefine, opt),
+		Table:        meta.Name,
+		Name:         camelName,
+		ModelName:    modelName,
+		DaoName:      gstr.CaseCamelLower(camelName) + "Dao",
+		StructDefine: structDefine,
+		Columns:      columns,
+		Indexes:      meta.Indexes,
+		Relations:    relations,
+		View:         meta.View,
+	}
+	for _, name := range meta.primaryKey() {
+		for _, column := range columns {
+			if column.Name == name {
+				data.PrimaryKeys = append(data.PrimaryKeys, column)
+			}
+		}
+	}
+	if len(data.PrimaryKeys) > 1 {
+		data.KeyName = camelName + "Key"
+	}
+	data.Finders = genFinders(columns, meta.Indexes)
+	for _, column := range columns {
+		if column.Enum != nil {
+			data.Enums = append(data.Enums, column.Enum)
+		}
+	}
+	data.Imports = append(data.Imports, enumImports(data.Enums)...)
+	data.DaoImports = genImports(strings.Join(data.daoTypes(), " "), opt)
+	return data
+}
+
+// 根据结构体中用到的类型生成 import
+func genImports(structDefine string, opt *ModelOptions) []string {
+	var imports []string
+	if gstr.Contains(structDefine, "time.Time") {
+		imports = append(imports, "time")
+	}
+	if gregex.IsMatchString(`\bsql\.Null`, structDefine) {
+		imports = append(imports, "database/sql")
+	}
+	if gregex.IsMatchString(`\bpq\.[A-Z]`, structDefine) {
+		imports = append(imports, "github.com/lib/pq")
+	}
+	if gregex.IsMatchString(`\bgorm\.[A-Z]`, structDefine) {
+		imports = append(imports, "gorm.io/gorm")
+	}
+	if gregex.IsMatchString(`\bdatatypes\.JSON\b`, structDefine) {
+		imports = append(imports, "gorm.io/datatypes")
+	}
+	if gregex.IsMatchString(`\bsoft_delete\.DeletedAt\b`, structDefine) {
+		imports = append(imports, "gorm.io/plugin/soft_delete")
+	}
+	if gregex.IsMatchString(`\bnull\.(String|Int|Float|Bool|Time)\b`, structDefine) {
+		imports = append(imports, "gopkg.in/guregu/null.v4")
+	}
+	// 自定义类型的包
+	for _, v := range opt.Types {
+		typeName, importPath := parseGoType(v)
+		if importPath == "" {
+			continue
+		}
+		typeName = gstr.TrimLeft(typeName, "*[]")
+		if !gregex.IsMatchString(`\b`+gregex.Quote(typeName)+`\b`, structDefine) {
+			continue
+		}
+		if !gstr.InArray(imports, importPath) {
+			imports = append(imports, importPath)
+		}
+	}
+	return imports
+}
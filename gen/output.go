@@ -0,0 +1,175 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gogf/gf/os/gcmd"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/os/glog"
+	"github.com/mattn/go-isatty"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// 预览生成的文件，已存在的自定义方法的文件不会修改
+func previewGenFile(file *genFile, opt *ModelOptions) string {
+	if file.mode == writeOnce && gfile.Exists(file.path) && !file.isLegacy() {
+		return fileUnchanged
+	}
+	return previewModelFile(file.path, file.content, opt)
+}
+
+// 按文件的处理方式写入，生成的文件总是覆盖，自定义方法的文件只生成一次，其他的按 overwrite 策略处理
+func writeGenFile(file *genFile, overwrite string, logger *glog.Logger) (string, error) {
+	if gfile.Exists(file.path) {
+		switch file.mode {
+		case writeGenerated:
+			if gfile.GetContents(file.path) == file.content {
+				return fileUnchanged, nil
+			}
+			overwrite = OverwriteForce
+		case writeOnce:
+			if !file.isLegacy() {
+				return fileUnchanged, nil
+			}
+			// 保留旧文件时和 *_gen.go 重复声明，包就编译不过了，不能跳过
+			legacyErr := fmt.Errorf("%w: %s, the generated code is moved to *_gen.go, move the custom methods out and re-run with -force or -backup", ErrLegacyFile, file.path)
+			if overwrite == OverwriteSkip || overwrite == OverwriteAsk && !isTerminal(os.Stdin) {
+				return "", legacyErr
+			}
+			status, err := writeModelFile(file.path, file.content, overwrite, logger)
+			if err == nil && status == fileSkipped {
+				return "", legacyErr
+			}
+			return status, err
+		}
+	}
+	return writeModelFile(file.path, file.content, overwrite, logger)
+}
+
+// 已存在的文件是不是旧版本生成的完整文件
+func (f *genFile) isLegacy() bool {
+	return f.legacy != "" && strings.Contains(gfile.GetContents(f.path), f.legacy)
+}
+
+// 不写入文件，只对比生成的内容和已有的文件，showDiff 时输出 unified diff
+func previewModelFile(path, content string, opt *ModelOptions) string {
+	var (
+		status  = fileUnchanged
+		old     string
+		oldName = path
+	)
+	switch {
+	case !gfile.Exists(path):
+		status = fileCreated
+		oldName = "/dev/null"
+	default:
+		old = gfile.GetContents(path)
+		if old != content {
+			status = fileOverwritten
+		}
+	}
+	if opt.Diff && status != fileUnchanged {
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(old),
+			B:        difflib.SplitLines(content),
+			FromFile: oldName,
+			ToFile:   path,
+			Context:  3,
+		})
+		fmt.Fprint(opt.output(), diff)
+	}
+	return status
+}
+
+// 写入生成的文件，文件已存在时按 overwrite 策略处理
+func writeModelFile(path, content, overwrite string, logger *glog.Logger) (string, error) {
+	status := fileCreated
+	if gfile.Exists(path) {
+		status = fileOverwritten
+		switch overwrite {
+		case OverwriteForce:
+		case OverwriteSkip:
+			return fileSkipped, nil
+		case OverwriteBackup:
+			if err := gfile.CopyFile(path, path+".bak"); err != nil {
+				return "", err
+			}
+			status = fileBackedUp
+		default:
+			// 非终端（CI、go:generate）中没法交互，直接跳过
+			if !isTerminal(os.Stdin) {
+				logger.Warningf("%s is exist and stdin is not a terminal, skipped, use -force to overwrite", path)
+				return fileSkipped, nil
+			}
+			s := gcmd.Scanf("the '%s' is exist, files might be overwrote, continue?[y/n]:", path)
+			if strings.EqualFold(s, "n") {
+				return fileSkipped, nil
+			}
+		}
+	}
+	if err := gfile.PutContents(path, content); err != nil {
+		return "", err
+	}
+	logger.Print("generated:", path)
+	return status, nil
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Summary 生成结果的汇总
+type Summary struct {
+	Created     []string
+	Overwritten []string
+	BackedUp    []string
+	Skipped     []string
+	Unchanged   []string
+}
+
+func (s *Summary) add(path, status string) {
+	switch status {
+	case fileCreated:
+		s.Created = append(s.Created, path)
+	case fileOverwritten:
+		s.Overwritten = append(s.Overwritten, path)
+	case fileBackedUp:
+		s.BackedUp = append(s.BackedUp, path)
+	case fileSkipped:
+		s.Skipped = append(s.Skipped, path)
+	case fileUnchanged:
+		s.Unchanged = append(s.Unchanged, path)
+	}
+}
+
+// Changed 新增或者有变化的文件数
+func (s *Summary) Changed() int {
+	return len(s.Created) + len(s.Overwritten) + len(s.BackedUp)
+}
+
+// Print 输出每种结果的文件数和文件
+func (s *Summary) Print(w io.Writer) {
+	groups := []struct {
+		status string
+		files  []string
+	}{
+		{fileCreated, s.Created},
+		{fileOverwritten, s.Overwritten},
+		{fileBackedUp, s.BackedUp},
+		{fileSkipped, s.Skipped},
+		{fileUnchanged, s.Unchanged},
+	}
+	counts := make([]string, 0, len(groups))
+	for _, group := range groups {
+		counts = append(counts, fmt.Sprintf("%s: %d", group.status, len(group.files)))
+	}
+	fmt.Fprintln(w, strings.Join(counts, ", "))
+	for _, group := range groups {
+		for _, file := range group.files {
+			fmt.Fprintf(w, "  %-12s %s\n", group.status, file)
+		}
+	}
+}
@@ -1,9 +1,11 @@
-package main
+package gen
 
 import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
@@ -13,7 +15,6 @@ import (
 
 	"github.com/gogf/gf/frame/g"
 	"github.com/gogf/gf/os/gfile"
-	"github.com/gogf/gf/os/glog"
 	"github.com/gogf/gf/text/gstr"
 )
 
@@ -23,7 +24,8 @@ const (
 	defaultPath  = "./fanone"
 )
 
-func GenProject(projectPath, projectName string) error {
+// GenProject 生成项目的目录结构并执行初始化脚本，脚本的输出写入 output，为 nil 时不输出
+func GenProject(projectPath, projectName string, output io.Writer) error {
 
 	if projectPath == "" {
 		projectPath = "./"
@@ -72,10 +74,10 @@ func GenProject(projectPath, projectName string) error {
 
 		case genConfigPath:
 			if err := gfile.Mkdir(genConfigPath); err != nil {
-				glog.Fatal("mkdir for generating path:%s failed: %v", genPath, err)
+				return fmt.Errorf("mkdir for generating path:%s failed: %w", genPath, err)
 			}
 			if err := gfile.Mkdir(genConfigPath + "local/"); err != nil {
-				glog.Fatal("mkdir for generating path:%s failed: %v", genPath, err)
+				return fmt.Errorf("mkdir for generating path:%s failed: %w", genPath, err)
 			}
 			yamlPath := genConfigPath + "local/config.yaml"
 			entityContent := gstr.ReplaceByMap(configYamlTemplate, g.MapStrStr{
@@ -94,7 +96,7 @@ func GenProject(projectPath, projectName string) error {
 
 		case genCmdPath:
 			if err := gfile.Mkdir(genCmdPath); err != nil {
-				glog.Fatal("mkdir for generating path:%s failed: %v", genPath, err)
+				return fmt.Errorf("mkdir for generating path:%s failed: %w", genPath, err)
 			}
 			cmdPath := genCmdPath + "main.go"
 			entityContent := gstr.ReplaceByMap(cmdTemplate, g.MapStrStr{
@@ -107,7 +109,7 @@ func GenProject(projectPath, projectName string) error {
 
 		case genRouterPath:
 			if err := gfile.Mkdir(genRouterPath); err != nil {
-				glog.Fatal("mkdir for generating path:%s failed: %v", genPath, err)
+				return fmt.Errorf("mkdir for generating path:%s failed: %w", genPath, err)
 			}
 			routerPath := genRouterPath + "router.go"
 			entityContent := gstr.ReplaceByMap(routerTemplate, g.MapStrStr{
@@ -119,7 +121,7 @@ func GenProject(projectPath, projectName string) error {
 
 		case genMiddlewarePath:
 			if err := gfile.Mkdir(genMiddlewarePath); err != nil {
-				glog.Fatal("mkdir for generating path:%s failed: %v", genPath, err)
+				return fmt.Errorf("mkdir for generating path:%s failed: %w", genPath, err)
 			}
 			middlewarePath := genMiddlewarePath + "cors.go"
 			entityContent := gstr.ReplaceByMap(middlewareCorsTemplate, g.MapStrStr{
@@ -131,15 +133,13 @@ func GenProject(projectPath, projectName string) error {
 
 		default:
 			if err := gfile.Mkdir(genPath); err != nil {
-				glog.Fatal("mkdir for generating path:%s failed: %v", genPath, err)
-				return err
+				return fmt.Errorf("mkdir for generating path:%s failed: %w", genPath, err)
 			}
 		}
 	}
 
 	if err := pingBaidu(); err != nil {
-		fmt.Println("网络异常", err)
-		return err
+		return fmt.Errorf("network error: %w", err)
 	}
 
 	entityContent := gstr.ReplaceByMap(ScriptCmdTemplate, g.MapStrStr{
@@ -147,16 +147,16 @@ func GenProject(projectPath, projectName string) error {
 	})
 	scriptPath := projectPath + "start.sh"
 	if err := writeFile(scriptPath, entityContent); err != nil {
-		fmt.Println("写入脚本错误", err)
-		return err
+		return fmt.Errorf("write start script failed: %w", err)
+	}
+	if output == nil {
+		output = ioutil.Discard
 	}
 	cmd := exec.Command("bash", scriptPath)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Println("启动脚本错误", err)
-		return err
+	cmd.Stdout, cmd.Stderr = output, output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run start script failed: %w", err)
 	}
-	fmt.Println("output", string(output))
 	return nil
 }
 
@@ -171,7 +171,7 @@ func getGolangVersion() (string, error) {
 func writeFile(path, content string) error {
 	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0666)
 	if err != nil {
-		fmt.Println("文件打开失败", err)
+		return fmt.Errorf("文件打开失败: %w", err)
 	}
 	defer file.Close()
 	// 写入文件时，使用带缓存的 *Writer
@@ -0,0 +1,295 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// 关联的类型
+const (
+	RelationBelongsTo = "belongs_to" // 子表上的 belongs to，如 UserInfo.Org
+	RelationHasMany   = "has_many"   // 父表上的 has many，如 Org.UserInfos
+	RelationMany2Many = "many2many"  // 通过只有两个外键的中间表关联，如 User.Roles
+)
+
+// 外键，联合外键的字段按顺序一一对应
+type foreignKey struct {
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Relation 根据外键生成的 gorm 关联字段
+type Relation struct {
+	Kind      string // belongs_to、has_many、many2many
+	Name      string // 结构体字段名，如 Org、UserInfos、Roles
+	Table     string // 关联的表
+	ModelName string // 关联的结构体名，如 OrgModel
+	GoType    string // 字段类型，如 *OrgModel、[]*RoleModel
+	Tag       string // 完整的结构体标签，不含反引号
+
+	gormTag string
+}
+
+// mysql 的外键，按表、外键、字段顺序排列
+const mysqlForeignKeysSql = `SELECT TABLE_NAME AS table_name, CONSTRAINT_NAME AS constraint_name, COLUMN_NAME AS column_name,
+	REFERENCED_TABLE_NAME AS ref_table, REFERENCED_COLUMN_NAME AS ref_column
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL
+ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION`
+
+// postgres 的外键，information_schema 中联合外键的字段对应不上，直接查 pg_constraint
+const pgForeignKeysSql = `SELECT cl.relname AS table_name, c.conname AS constraint_name, a.attname AS column_name,
+	rcl.relname AS ref_table, ra.attname AS ref_column
+FROM pg_constraint c
+JOIN pg_class cl ON cl.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = cl.relnamespace
+JOIN pg_class rcl ON rcl.oid = c.confrelid
+JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refnum, ord) ON true
+JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refnum
+WHERE c.contype = 'f' AND n.nspname = current_schema()
+ORDER BY cl.relname, c.conname, k.ord`
+
+// 获取库中所有的外键
+func foreignKeys(ctx context.Context, db gdb.DB, dialect string) ([]*foreignKey, error) {
+	switch dialect {
+	case DialectSqlite:
+		return sqliteForeignKeys(ctx, db)
+	case DialectPostgres:
+		return queryForeignKeys(ctx, db, pgForeignKeysSql)
+	default:
+		return queryForeignKeys(ctx, db, mysqlForeignKeysSql)
+	}
+}
+
+// 查询结果每行是外键中的一个字段，按外键合并
+func queryForeignKeys(ctx context.Context, db gdb.DB, sql string) ([]*foreignKey, error) {
+	result, err := db.Ctx(ctx).GetAll(sql)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		fks  []*foreignKey
+		last *foreignKey
+	)
+	for _, m := range result {
+		table, name := m["table_name"].String(), m["constraint_name"].String()
+		if last == nil || last.Table != table || last.Name != name {
+			last = &foreignKey{Name: name, Table: table, RefTable: m["ref_table"].String()}
+			fks = append(fks, last)
+		}
+		last.Columns = append(last.Columns, m["column_name"].String())
+		last.RefColumns = append(last.RefColumns, m["ref_column"].String())
+	}
+	return fks, nil
+}
+
+// 通过 PRAGMA foreign_key_list 获取 sqlite 的外键，没有写引用字段时引用的是父表的主键
+func sqliteForeignKeys(ctx context.Context, db gdb.DB) ([]*foreignKey, error) {
+	tables, err := sqliteTables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var fks []*foreignKey
+	for _, table := range tables {
+		result, err := db.Ctx(ctx).GetAll(fmt.Sprintf("PRAGMA foreign_key_list(%s)", sqliteQuote(table)))
+		if err != nil {
+			return nil, err
+		}
+		byId := make(map[int]*foreignKey)
+		var ids []int
+		for _, m := range result {
+			id := m["id"].Int()
+			fk, ok := byId[id]
+			if !ok {
+				fk = &foreignKey{Name: fmt.Sprintf("%s_fk_%d", table, id), Table: table, RefTable: m["table"].String()}
+				byId[id] = fk
+				ids = append(ids, id)
+			}
+			fk.Columns = append(fk.Columns, m["from"].String())
+			fk.RefColumns = append(fk.RefColumns, m["to"].String())
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			fk := byId[id]
+			if fk.RefColumns[0] == "" {
+				if fk.RefColumns, err = sqlitePrimaryKey(ctx, db, fk.RefTable); err != nil {
+					return nil, err
+				}
+			}
+			fks = append(fks, fk)
+		}
+	}
+	return fks, nil
+}
+
+// sqlite 表的主键字段，按主键中的顺序
+func sqlitePrimaryKey(ctx context.Context, db gdb.DB, table string) ([]string, error) {
+	result, err := db.Ctx(ctx).GetAll(fmt.Sprintf("PRAGMA table_info(%s)", sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, 1)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i]["pk"].Int() < result[j]["pk"].Int()
+	})
+	for _, m := range result {
+		if m["pk"].Int() > 0 {
+			columns = append(columns, m["name"].String())
+		}
+	}
+	return columns, nil
+}
+
+// 根据外键生成每个表的关联，只处理单字段的外键，关联的表也需要在这次生成的表中，否则关联的结构体不存在
+func genRelations(fks []*foreignKey, tables []string, opt *ModelOptions) map[string][]*Relation {
+	selected := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		selected[table] = true
+	}
+	var (
+		usable   []*foreignKey
+		byTable  = make(map[string][]*foreignKey)
+		refCount = make(map[string]int) // 子表指向同一个父表的外键数
+	)
+	for _, fk := range fks {
+		if len(fk.Columns) != 1 || len(fk.RefColumns) != 1 || !selected[fk.Table] || !selected[fk.RefTable] {
+			continue
+		}
+		usable = append(usable, fk)
+		byTable[fk.Table] = append(byTable[fk.Table], fk)
+		refCount[fk.Table+"."+fk.RefTable]++
+	}
+
+	// 只有两个外键、指向两个不同的表的是中间表，父表上同时生成 has many 和 many2many
+	isJoinTable := func(table string) bool {
+		fks := byTable[table]
+		return len(fks) == 2 && fks[0].RefTable != fks[1].RefTable
+	}
+
+	relations := make(map[string][]*Relation)
+	for _, fk := range usable {
+		column, refColumn := fk.Columns[0], fk.RefColumns[0]
+		gormTag := fmt.Sprintf("foreignKey:%s;references:%s", gstr.CaseCamel(column), gstr.CaseCamel(refColumn))
+		multiple := refCount[fk.Table+"."+fk.RefTable] > 1
+		name := belongsToName(column, fk.RefTable, multiple, opt)
+		relations[fk.Table] = append(relations[fk.Table], &Relation{
+			Kind:      RelationBelongsTo,
+			Name:      name,
+			Table:     fk.RefTable,
+			ModelName: relationModelName(fk.RefTable, opt),
+			GoType:    "*" + relationModelName(fk.RefTable, opt),
+			gormTag:   gormTag,
+		})
+		name = plural(relationCamelName(fk.Table, opt))
+		if multiple {
+			name += "By" + gstr.CaseCamel(trimIdSuffix(column))
+		}
+		relations[fk.RefTable] = append(relations[fk.RefTable], &Relation{
+			Kind:      RelationHasMany,
+			Name:      name,
+			Table:     fk.Table,
+			ModelName: relationModelName(fk.Table, opt),
+			GoType:    "[]*" + relationModelName(fk.Table, opt),
+			gormTag:   gormTag,
+		})
+	}
+
+	joinTables := make([]string, 0, len(byTable))
+	for table := range byTable {
+		if isJoinTable(table) {
+			joinTables = append(joinTables, table)
+		}
+	}
+	sort.Strings(joinTables)
+	for _, table := range joinTables {
+		fks := byTable[table]
+		for i, fk := range fks {
+			other := fks[1-i]
+			name := plural(relationCamelName(other.RefTable, opt))
+			gormTag := fmt.Sprintf("many2many:%s;foreignKey:%s;joinForeignKey:%s;references:%s;joinReferences:%s",
+				table, gstr.CaseCamel(fk.RefColumns[0]), gstr.CaseCamel(fk.Columns[0]),
+				gstr.CaseCamel(other.RefColumns[0]), gstr.CaseCamel(other.Columns[0]))
+			relations[fk.RefTable] = append(relations[fk.RefTable], &Relation{
+				Kind:      RelationMany2Many,
+				Name:      name,
+				Table:     other.RefTable,
+				ModelName: relationModelName(other.RefTable, opt),
+				GoType:    "[]*" + relationModelName(other.RefTable, opt),
+				gormTag:   gormTag,
+			})
+		}
+	}
+	return relations
+}
+
+// 关联的字段名和表中的字段重名时加上 Ref 后缀，生成标签
+func finishRelations(columns []*Column, relations []*Relation, opt *ModelOptions) []*Relation {
+	used := make(map[string]bool, len(columns)+len(relations))
+	for _, column := range columns {
+		used[column.GoName] = true
+	}
+	result := make([]*Relation, 0, len(relations))
+	for _, r := range relations {
+		relation := *r
+		for used[relation.Name] {
+			relation.Name += "Ref"
+		}
+		used[relation.Name] = true
+		relation.Tag = relationTag(relation.Name, relation.gormTag, opt)
+		result = append(result, &relation)
+	}
+	return result
+}
+
+// belongs to 的字段名，org_id -> Org，外键字段不是 _id 结尾时用父表的名字，
+// 有多个外键指向同一个表时用字段名，如 from_org -> FromOrg
+func belongsToName(column, refTable string, multiple bool, opt *ModelOptions) string {
+	if name := trimIdSuffix(column); name != column || multiple {
+		return gstr.CaseCamel(name)
+	}
+	return relationCamelName(refTable, opt)
+}
+
+func trimIdSuffix(column string) string {
+	if strings.HasSuffix(strings.ToLower(column), "_id") && len(column) > 3 {
+		return column[:len(column)-3]
+	}
+	return column
+}
+
+func relationCamelName(table string, opt *ModelOptions) string {
+	return gstr.CaseCamel(stripTablePrefix(table, opt.StripPrefix))
+}
+
+func relationModelName(table string, opt *ModelOptions) string {
+	return relationCamelName(table, opt) + "Model"
+}
+
+// 关联字段的标签，额外的标签中只生成 json，没有加载关联时不输出
+func relationTag(name, gormTag string, opt *ModelOptions) string {
+	tags := []string{`gorm:"` + gormTag + `"`}
+	for _, tag := range opt.Tags {
+		if tag.Name == "json" {
+			tags = append(tags, fmt.Sprintf(`json:"%s,omitempty"`, tagFieldName(name, tag.Style)))
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// 关联字段在 tablewriter 中的一行
+func relationFields(relation *Relation) []string {
+	return []string{
+		"   #" + relation.Name,
+		" #" + relation.GoType,
+		" #`" + relation.Tag + "`",
+	}
+}
@@ -0,0 +1,103 @@
+package gen
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDDLForeignKeys(t *testing.T) {
+	src, err := openDDLSource("testdata/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fks, err := src.ForeignKeys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, fk := range fks {
+		got = append(got, fk.Name+":"+fk.Table+"("+strings.Join(fk.Columns, ",")+")->"+fk.RefTable+"("+strings.Join(fk.RefColumns, ",")+")")
+	}
+	want := "fk_user_org:user_info(org_id)->org(id) user_role_link_role_id_fkey:user_role_link(role_id)->role(id) fk_link_user:user_role_link(user_id)->user_info(id)"
+	if strings.Join(got, " ") != want {
+		t.Errorf("foreign keys = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	// REFERENCES 后面的 ON UPDATE 不能当成字段的属性
+	link, _ := src.Table(context.Background(), "user_role_link")
+	if extra := link.Fields["role_id"].Extra; extra != "" {
+		t.Errorf("role_id extra = %q, want empty", extra)
+	}
+}
+
+func TestGenRelations(t *testing.T) {
+	fks := []*foreignKey{
+		{Table: "user_info", Columns: []string{"org_id"}, RefTable: "org", RefColumns: []string{"id"}},
+		{Table: "user_role", Columns: []string{"user_id"}, RefTable: "user_info", RefColumns: []string{"id"}},
+		{Table: "user_role", Columns: []string{"role_id"}, RefTable: "role", RefColumns: []string{"id"}},
+		{Table: "transfer", Columns: []string{"from_org"}, RefTable: "org", RefColumns: []string{"id"}},
+		{Table: "transfer", Columns: []string{"to_org"}, RefTable: "org", RefColumns: []string{"id"}},
+		// 关联的表不在这次生成的表中
+		{Table: "user_info", Columns: []string{"team_id"}, RefTable: "team", RefColumns: []string{"id"}},
+	}
+	opt := &ModelOptions{Tags: []StructTag{{"json", TagStyleCamel}}}
+	relations := genRelations(fks, []string{"user_info", "org", "user_role", "role", "transfer"}, opt)
+	cases := map[string]string{
+		"user_info": `Org *OrgModel gorm:"foreignKey:OrgId;references:Id" json:"org,omitempty"
+UserRoles []*UserRoleModel gorm:"foreignKey:UserId;references:Id" json:"userRoles,omitempty"
+Roles []*RoleModel gorm:"many2many:user_role;foreignKey:Id;joinForeignKey:UserId;references:Id;joinReferences:RoleId" json:"roles,omitempty"`,
+		"org": `UserInfos []*UserInfoModel gorm:"foreignKey:OrgId;references:Id" json:"userInfos,omitempty"
+TransfersByFromOrg []*TransferModel gorm:"foreignKey:FromOrg;references:Id" json:"transfersByFromOrg,omitempty"
+TransfersByToOrg []*TransferModel gorm:"foreignKey:ToOrg;references:Id" json:"transfersByToOrg,omitempty"`,
+		// 和字段重名时加上 Ref 后缀
+		"transfer": `FromOrgRef *OrgModel gorm:"foreignKey:FromOrg;references:Id" json:"fromOrgRef,omitempty"
+ToOrgRef *OrgModel gorm:"foreignKey:ToOrg;references:Id" json:"toOrgRef,omitempty"`,
+	}
+	for table, want := range cases {
+		var columns []*Column
+		if table == "transfer" {
+			columns = []*Column{{GoName: "FromOrg"}, {GoName: "ToOrg"}}
+		}
+		var got []string
+		for _, r := range finishRelations(columns, relations[table], opt) {
+			got = append(got, r.Name+" "+r.GoType+" "+r.Tag)
+		}
+		if strings.Join(got, "\n") != want {
+			t.Errorf("%s relations:\n%s\nwant:\n%s", table, strings.Join(got, "\n"), want)
+		}
+	}
+}
+
+func TestGenModelSqliteRelations(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "schema.db")
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Exec(`CREATE TABLE org (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE member (id INTEGER PRIMARY KEY, org_id INTEGER NOT NULL REFERENCES org, name TEXT NOT NULL)`)
+	conn.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genPath := filepath.Join(dir, "dao")
+	if _, err = GenModel(context.Background(), ModelOptions{DSN: "sqlite://" + dbPath, Path: genPath}); err != nil {
+		t.Fatal(err)
+	}
+	member, _ := ioutil.ReadFile(filepath.Join(genPath, "member_gen.go"))
+	org, _ := ioutil.ReadFile(filepath.Join(genPath, "org_dao_gen.go"))
+	for content, want := range map[string]string{
+		string(member): `gorm:"foreignKey:OrgId;references:Id"`,
+		string(org):    `OrgPreloadMembers = "Members"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated model missing %q:\n%s", want, content)
+		}
+	}
+}
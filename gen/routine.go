@@ -0,0 +1,317 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// 存储过程和函数
+const (
+	RoutineProcedure = "PROCEDURE"
+	RoutineFunction  = "FUNCTION"
+)
+
+// 参数的类型
+const (
+	ParamIn    = "IN"
+	ParamOut   = "OUT"
+	ParamInOut = "INOUT"
+)
+
+// mysql 函数的返回值在 PARAMETERS 中的 ORDINAL_POSITION 为 0
+const mysqlRoutinesSql = `
+SELECT r.ROUTINE_NAME AS routine_name,
+       r.ROUTINE_TYPE AS routine_type,
+       r.SPECIFIC_NAME AS specific_name,
+       '' AS return_type,
+       p.ORDINAL_POSITION AS position,
+       p.PARAMETER_MODE AS mode,
+       p.PARAMETER_NAME AS name,
+       p.DTD_IDENTIFIER AS type
+FROM information_schema.ROUTINES r
+LEFT JOIN information_schema.PARAMETERS p
+  ON p.SPECIFIC_SCHEMA = r.ROUTINE_SCHEMA AND p.SPECIFIC_NAME = r.SPECIFIC_NAME AND p.ROUTINE_TYPE = r.ROUTINE_TYPE
+WHERE r.ROUTINE_SCHEMA = DATABASE()
+ORDER BY r.ROUTINE_NAME, r.ROUTINE_TYPE, p.ORDINAL_POSITION`
+
+// pg 的函数可以重载，按 specific_name 区分，specific_name 为 函数名_oid；扩展安装的函数不生成
+const pgRoutinesSql = `
+SELECT r.routine_name AS routine_name,
+       r.routine_type AS routine_type,
+       r.specific_name AS specific_name,
+       COALESCE(r.type_udt_name, '') AS return_type,
+       p.ordinal_position AS position,
+       p.parameter_mode AS mode,
+       p.parameter_name AS name,
+       p.udt_name AS type
+FROM information_schema.routines r
+LEFT JOIN information_schema.parameters p
+  ON p.specific_schema = r.specific_schema AND p.specific_name = r.specific_name
+WHERE r.specific_schema = current_schema() AND r.routine_type IN ('FUNCTION', 'PROCEDURE')
+  AND NOT EXISTS (
+    SELECT 1 FROM pg_depend d
+    JOIN pg_proc f ON f.oid = d.objid
+    WHERE d.classid = 'pg_proc'::regclass AND d.deptype = 'e' AND f.proname || '_' || f.oid = r.specific_name
+  )
+ORDER BY r.routine_name, r.specific_name, p.ordinal_position`
+
+// pg 中不能直接调用的函数的返回类型
+var pgSkipReturnTypes = []string{"trigger", "event_trigger", "internal", "language_handler", "fdw_handler", "index_am_handler", "tsm_handler", "table_am_handler"}
+
+// Routine 存储过程或函数，生成 RoutineDao 中调用的方法
+type Routine struct {
+	Name     string          // 数据库中的名字，如 calc_order_amount
+	GoName   string          // 方法名，如 CalcOrderAmount
+	Kind     string          // PROCEDURE 或 FUNCTION
+	Params   []*RoutineParam // 所有的参数，按定义的顺序
+	Return   *RoutineParam   // 函数的返回值，没有返回值或者有 OUT 参数时为空
+	Query    string          // 调用的 sql
+	Args     []string        // Query 的参数
+	Select   string          // mysql 存储过程通过会话变量读取 OUT 参数的 sql
+	Session  bool            // 需要在同一个连接中执行 SET、CALL、SELECT
+	Outs     []*RoutineParam // 方法的返回值，OUT、INOUT 参数和函数的返回值
+	Vars     []*RoutineParam // 方法中需要定义的返回值变量，INOUT 直接使用参数
+	specific string
+	retType  string
+}
+
+// RoutineParam 存储过程或函数的参数
+type RoutineParam struct {
+	Name   string // 数据库中的参数名
+	GoName string // 方法的参数名
+	Mode   string // IN、OUT、INOUT
+	DBType string // 数据库类型
+	GoType string // 按 types 映射后的 Go 类型
+}
+
+// In 是否作为方法的参数
+func (p *RoutineParam) In() bool {
+	return p.Mode != ParamOut
+}
+
+// Out 是否作为方法的返回值
+func (p *RoutineParam) Out() bool {
+	return p.Mode == ParamOut || p.Mode == ParamInOut
+}
+
+// 读取存储过程和函数，sqlite 没有存储过程
+func (s *dbSource) Routines(ctx context.Context) ([]*Routine, error) {
+	var (
+		result gdb.Result
+		err    error
+	)
+	switch s.dialect {
+	case DialectMysql:
+		result, err = s.db.Ctx(ctx).GetAll(mysqlRoutinesSql)
+	case DialectPostgres:
+		result, err = s.db.Ctx(ctx).GetAll(pgRoutinesSql)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return groupRoutines(result), nil
+}
+
+// 按 specific_name 分组，mysql 中同名的存储过程和函数 specific_name 相同，结果已经按参数的顺序排好序了
+func groupRoutines(result gdb.Result) []*Routine {
+	var (
+		routines []*Routine
+		last     *Routine
+	)
+	for _, m := range result {
+		specific := m["specific_name"].String()
+		kind := gstr.ToUpper(m["routine_type"].String())
+		if last == nil || last.specific != specific || last.Kind != kind {
+			last = &Routine{
+				Name:     m["routine_name"].String(),
+				Kind:     kind,
+				specific: specific,
+				retType:  m["return_type"].String(),
+			}
+			routines = append(routines, last)
+		}
+		// 没有参数的存储过程
+		if m["position"].IsNil() {
+			continue
+		}
+		param := &RoutineParam{
+			Name:   m["name"].String(),
+			Mode:   gstr.ToUpper(m["mode"].String()),
+			DBType: m["type"].String(),
+		}
+		if m["position"].Int() == 0 {
+			// mysql 函数的返回值
+			last.retType = param.DBType
+			continue
+		}
+		if param.Mode == "" {
+			param.Mode = ParamIn
+		}
+		last.Params = append(last.Params, param)
+	}
+	return routines
+}
+
+// 按 types 映射参数的类型，生成调用的 sql，不能调用的函数和重载的函数跳过
+func genRoutines(routines []*Routine, opt *ModelOptions) []*Routine {
+	var (
+		result []*Routine
+		names  = make(map[string]bool)
+	)
+	for _, r := range routines {
+		if opt.Dialect == DialectPostgres && gstr.InArray(pgSkipReturnTypes, r.retType) {
+			continue
+		}
+		r.GoName = gstr.CaseCamel(r.Name)
+		if names[r.GoName] {
+			opt.logger().Warningf("routine %s is overloaded, only the first one is generated", r.Name)
+			continue
+		}
+		names[r.GoName] = true
+		genRoutine(r, opt)
+		result = append(result, r)
+	}
+	return result
+}
+
+func genRoutine(r *Routine, opt *ModelOptions) {
+	for i, p := range r.Params {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i+1)
+		}
+		p.GoName = paramName(name)
+		p.GoType = routineTypeName(r.Name, name, p.DBType, opt)
+	}
+	var hasOut bool
+	for _, p := range r.Params {
+		if p.Out() {
+			hasOut = true
+		}
+	}
+	// pg 中 returns void 的函数和有 OUT 参数的函数返回的 record 不需要返回值
+	if r.Kind == RoutineFunction && r.retType != "" && r.retType != "void" && r.retType != "record" && !hasOut {
+		r.Return = &RoutineParam{Name: "return", GoName: "r", Mode: ParamOut, DBType: r.retType}
+		r.Return.GoType = routineTypeName(r.Name, "return", r.retType, opt)
+	}
+
+	var (
+		placeholders []string
+		selects      []string
+	)
+	for _, p := range r.Params {
+		switch {
+		// mysql 的 OUT 参数通过会话变量传递
+		case opt.Dialect != DialectPostgres && r.Kind == RoutineProcedure && p.Out():
+			placeholders = append(placeholders, "@"+p.Name)
+			selects = append(selects, "@"+p.Name)
+			r.Session = true
+		case p.In():
+			placeholders = append(placeholders, "?")
+			r.Args = append(r.Args, p.GoName)
+		// pg 的 OUT 参数，存储过程中传 NULL，函数中不传
+		case r.Kind == RoutineProcedure:
+			placeholders = append(placeholders, "NULL")
+		}
+		if p.Out() {
+			r.Outs = append(r.Outs, p)
+			if p.Mode == ParamOut {
+				r.Vars = append(r.Vars, p)
+			}
+		}
+	}
+	if r.Return != nil {
+		r.Outs = append(r.Outs, r.Return)
+		r.Vars = append(r.Vars, r.Return)
+	}
+
+	call := quoteRoutineName(r.Name, opt.Dialect) + "(" + strings.Join(placeholders, ", ") + ")"
+	switch {
+	case r.Kind == RoutineProcedure:
+		r.Query = "CALL " + call
+	case hasOut:
+		r.Query = "SELECT * FROM " + call
+	default:
+		r.Query = "SELECT " + call
+	}
+	if r.Session {
+		r.Select = "SELECT " + strings.Join(selects, ", ")
+	}
+}
+
+// sql 中的存储过程名，mysql 用反引号，pg 用双引号
+func quoteRoutineName(name, dialect string) string {
+	if dialect == DialectPostgres {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// 参数的类型和字段一样按数据库类型映射，也可以在 types 中用 routine.param 指定，函数的返回值为 routine.return
+func routineTypeName(routine, param, dbType string, opt *ModelOptions) string {
+	field := &gdb.TableField{Name: param, Type: dbType}
+	if typeName, _, ok := overrideTypeName(routine, field, opt.Types); ok {
+		return typeName
+	}
+	typeName, _ := columnTypeName(field, opt)
+	return typeName
+}
+
+// 读取存储过程和函数，生成 routines_gen.go
+func genRoutinesFile(ctx context.Context, src schemaSource, templates []*fileTemplate, opt *ModelOptions, write func(table string, file *genFile) error) error {
+	routines, err := src.Routines(ctx)
+	if err != nil {
+		return fmt.Errorf("get routines failed: %w", err)
+	}
+	routines = genRoutines(routines, opt)
+	if len(routines) == 0 {
+		opt.logger().Warningf("no stored procedure or function found")
+		return nil
+	}
+	file, err := genRoutineFile(routines, templates, opt)
+	if err != nil {
+		return err
+	}
+	return write("routines", file)
+}
+
+// 生成 dao 目录中的 routines_gen.go
+func genRoutineFile(routines []*Routine, templates []*fileTemplate, opt *ModelOptions) (*genFile, error) {
+	tpl, err := findTemplate(templates, routineTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var types []string
+	for _, r := range routines {
+		for _, p := range r.Params {
+			types = append(types, p.GoType)
+		}
+		if r.Return != nil {
+			types = append(types, r.Return.GoType)
+		}
+	}
+	var imports []string
+	for _, importPath := range genImports(strings.Join(types, " "), opt) {
+		if importPath != "gorm.io/gorm" {
+			imports = append(imports, importPath)
+		}
+	}
+	data := map[string]interface{}{
+		"Package":  opt.daoPackage(),
+		"Imports":  imports,
+		"Routines": routines,
+	}
+	path := filepath.Join(opt.daoPath(), "routines_gen.go")
+	content, err := tpl.render(path, data)
+	if err != nil {
+		return nil, err
+	}
+	return &genFile{path: path, content: content, mode: writeGenerated}, nil
+}
@@ -0,0 +1,121 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/container/gvar"
+	"github.com/gogf/gf/database/gdb"
+)
+
+// 和 information_schema 查询结果一样的行，position 为 nil 时是没有参数的存储过程
+func routineRows(rows [][]interface{}) gdb.Result {
+	result := make(gdb.Result, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, gdb.Record{
+			"routine_name":  gvar.New(row[0]),
+			"routine_type":  gvar.New(row[1]),
+			"specific_name": gvar.New(row[2]),
+			"return_type":   gvar.New(row[3]),
+			"position":      gvar.New(row[4]),
+			"mode":          gvar.New(row[5]),
+			"name":          gvar.New(row[6]),
+			"type":          gvar.New(row[7]),
+		})
+	}
+	return result
+}
+
+func mysqlTestRoutines() []*Routine {
+	return groupRoutines(routineRows([][]interface{}{
+		{"calc_order", "FUNCTION", "calc_order", "", 0, nil, nil, "decimal(10,2)"},
+		{"calc_order", "FUNCTION", "calc_order", "", 1, nil, "order_id", "bigint unsigned"},
+		{"cleanup", "PROCEDURE", "cleanup", "", nil, nil, nil, nil},
+		{"settle", "PROCEDURE", "settle", "", 1, "IN", "order_id", "bigint"},
+		{"settle", "PROCEDURE", "settle", "", 2, "OUT", "amount", "decimal(10,2)"},
+		{"settle", "PROCEDURE", "settle", "", 3, "INOUT", "cnt", "int"},
+	}))
+}
+
+func pgTestRoutines() []*Routine {
+	return groupRoutines(routineRows([][]interface{}{
+		{"audit", "FUNCTION", "audit_1", "trigger", nil, nil, nil, nil},
+		{"order_stat", "FUNCTION", "order_stat_2", "record", 1, "IN", "p_id", "int8"},
+		{"order_stat", "FUNCTION", "order_stat_2", "record", 2, "OUT", "total", "numeric"},
+		{"order_stat", "FUNCTION", "order_stat_2", "record", 3, "OUT", "cnt", "int4"},
+		{"order_total", "FUNCTION", "order_total_3", "numeric", 1, "IN", "p_id", "int8"},
+		{"order_total", "FUNCTION", "order_total_4", "numeric", 1, "IN", "p_code", "varchar"},
+		{"touch", "PROCEDURE", "touch_5", "", 1, "INOUT", "n", "int4"},
+		{"touch", "PROCEDURE", "touch_5", "", 2, "IN", "", "text"},
+	}))
+}
+
+func TestGenRoutines(t *testing.T) {
+	cases := []struct {
+		dialect  string
+		routines []*Routine
+		types    map[string]string
+		want     []string
+	}{
+		{DialectMysql, mysqlTestRoutines(), map[string]string{"calc_order.return": "*float64"}, []string{
+			"CalcOrder(orderId uint64) *float64 SELECT `calc_order`(?) [orderId]",
+			"Cleanup() CALL `cleanup`() []",
+			"Settle(orderId int64 cnt int32) float64 int32 CALL `settle`(?, @amount, @cnt) [orderId] SELECT @amount, @cnt",
+		}},
+		// 触发器函数和重载的函数跳过
+		{DialectPostgres, pgTestRoutines(), nil, []string{
+			`OrderStat(pId int64) float64 int32 SELECT * FROM "order_stat"(?) [pId]`,
+			`OrderTotal(pId int64) float64 SELECT "order_total"(?) [pId]`,
+			`Touch(n int32 arg2 string) int32 CALL "touch"(?, ?) [n arg2]`,
+		}},
+	}
+	for _, c := range cases {
+		var got []string
+		for _, r := range genRoutines(c.routines, &ModelOptions{Dialect: c.dialect, Types: c.types}) {
+			var params, outs []string
+			for _, p := range r.Params {
+				if p.In() {
+					params = append(params, p.GoName+" "+p.GoType)
+				}
+			}
+			for _, p := range r.Outs {
+				outs = append(outs, p.GoType)
+			}
+			s := r.GoName + "(" + strings.Join(params, " ") + ") " + strings.Join(append(outs, r.Query), " ") + " [" + strings.Join(r.Args, " ") + "]"
+			if r.Select != "" {
+				s += " " + r.Select
+			}
+			got = append(got, s)
+		}
+		if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+			t.Errorf("%s routines:\n%s\nwant:\n%s", c.dialect, strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+		}
+	}
+}
+
+func TestGenRoutineFile(t *testing.T) {
+	templates, err := loadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt := &ModelOptions{Dialect: DialectMysql, Path: "dao", Package: "dao"}
+	file, err := genRoutineFile(genRoutines(mysqlTestRoutines(), opt), templates, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.path != "dao/routines_gen.go" || file.mode != writeGenerated {
+		t.Errorf("unexpected routine file: %s %s", file.path, file.mode)
+	}
+	for _, want := range []string{
+		"func (d *RoutineDao) CalcOrder(ctx context.Context, orderId uint64) (float64, error) {",
+		"Raw(\"SELECT `calc_order`(?)\", orderId).Row().Scan(&r)",
+		"return d.db.WithContext(ctx).Exec(\"CALL `cleanup`()\").Error",
+		`conn.Exec("SET @cnt = ?", cnt)`,
+		`conn.Raw("SELECT @amount, @cnt").Row().Scan(&amount, &cnt)`,
+		"return amount, cnt, err",
+	} {
+		if !strings.Contains(file.content, want) {
+			t.Errorf("routine file missing %q:\n%s", want, file.content)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/text/gstr"
+	"gopkg.in/yaml.v2"
+)
+
+// openSource 使用的 gdb 配置分组的序号
+var dbGroupSeq int64
+
+type Config struct {
+	Mysql Mysql `yaml:"mysql"`
+}
+
+type ConfigMap struct {
+	Mysql map[string]Mysql `yaml:"mysql"`
+}
+
+// 配置文件中生成 model 的配置
+type ModelConfig struct {
+	Types       map[string]string `yaml:"types"`
+	Conventions *Conventions      `yaml:"conventions"`
+	Layout      string            `yaml:"layout"`
+	Gen         []*GenJob         `yaml:"gen"`
+}
+
+type Mysql struct {
+	Dialect  string `yaml:"dialect"`
+	DbHost   string `yaml:"dbHost"`
+	DbPort   string `yaml:"dbPort"`
+	DBName   string `yaml:"dbName"`
+	UserName string `yaml:"userName"`
+	Password string `yaml:"password"`
+	Charset  string `yaml:"charset"`
+}
+
+// 表结构的来源，数据库或者 ddl 文件
+type schemaSource interface {
+	Tables(ctx context.Context) ([]string, error)
+	Views(ctx context.Context) ([]string, error)
+	Table(ctx context.Context, name string) (*tableMeta, error)
+	Routines(ctx context.Context) ([]*Routine, error)
+	ForeignKeys(ctx context.Context) ([]*foreignKey, error)
+	Close(ctx context.Context) error
+}
+
+// 根据选项打开表结构的来源，设置了 DDL 时不连接数据库
+func openSource(opts *ModelOptions) (schemaSource, error) {
+	if opts.DDL != "" {
+		opts.Dialect = DialectMysql
+		return openDDLSource(opts.DDL)
+	}
+
+	var (
+		dbNode gdb.ConfigNode
+		err    error
+	)
+	if opts.DSN != "" {
+		dbNode, err = parseDSN(opts.DSN)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// 从配置文件中读取
+		mysqlInfo, err := getMysqlConfig(opts.ConfigPath, opts.Key)
+		if err != nil {
+			return nil, err
+		}
+		dbNode = gdb.ConfigNode{
+			Host:    mysqlInfo.DbHost,
+			Port:    mysqlInfo.DbPort,
+			User:    mysqlInfo.UserName,
+			Pass:    mysqlInfo.Password,
+			Name:    mysqlInfo.DBName,
+			Type:    normalizeDialect(mysqlInfo.Dialect),
+			Charset: mysqlInfo.Charset,
+		}
+	}
+	opts.Dialect = dbNode.Type
+
+	// gdb 的配置是全局的，每次使用单独的分组，并发调用 GenModel 时不会互相覆盖
+	group := fmt.Sprintf("fgen_%d", atomic.AddInt64(&dbGroupSeq, 1))
+	gdb.SetConfigGroup(group, gdb.ConfigGroup{dbNode})
+	db, err := gdb.New(group)
+	if err != nil {
+		return nil, fmt.Errorf("database initialization failed: %w", err)
+	}
+	return &dbSource{db: db, dialect: dbNode.Type}, nil
+}
+
+// 从数据库中读取表结构
+type dbSource struct {
+	db      gdb.DB
+	dialect string
+	fields  map[string]map[string]*gdb.TableField // prefetch 读取的所有表的字段，为空时逐个表查询
+	indexes map[string][]*Index                   // prefetch 读取的所有表的索引，为 nil 时逐个表查询
+	views   map[string]bool                       // Views 读取的视图
+}
+
+func (s *dbSource) Tables(ctx context.Context) ([]string, error) {
+	switch s.dialect {
+	case DialectSqlite:
+		return sqliteTables(ctx, s.db)
+	case DialectPostgres:
+		return s.db.Tables(ctx)
+	default:
+		return mysqlTables(ctx, s.db)
+	}
+}
+
+func (s *dbSource) Table(ctx context.Context, name string) (*tableMeta, error) {
+	fields, ok := s.fields[name]
+	if !ok {
+		var err error
+		if fields, err = tableFields(ctx, s.db, s.dialect, name); err != nil {
+			return nil, err
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotFound, name)
+	}
+	// 视图没有索引
+	if s.views[name] {
+		return &tableMeta{Name: name, Fields: fields, View: true}, nil
+	}
+	indexes := s.indexes[name]
+	if s.indexes == nil {
+		var err error
+		if indexes, err = tableIndexes(ctx, s.db, s.dialect, name); err != nil {
+			return nil, err
+		}
+	}
+	return &tableMeta{
+		Name:    name,
+		Fields:  fields,
+		Indexes: indexes,
+	}, nil
+}
+
+func (s *dbSource) ForeignKeys(ctx context.Context) ([]*foreignKey, error) {
+	return foreignKeys(ctx, s.db, s.dialect)
+}
+
+func (s *dbSource) Close(ctx context.Context) error {
+	return s.db.Close(ctx)
+}
+
+// 从 ddl 文件中读取表结构
+type ddlSource struct {
+	path   string
+	names  []string
+	tables map[string]*tableMeta
+}
+
+func openDDLSource(pattern string) (*ddlSource, error) {
+	tables, err := readDDLTables(pattern)
+	if err != nil {
+		return nil, err
+	}
+	s := &ddlSource{
+		path:   pattern,
+		tables: make(map[string]*tableMeta, len(tables)),
+	}
+	for _, t := range tables {
+		s.names = append(s.names, t.Name)
+		s.tables[t.Name] = t
+	}
+	return s, nil
+}
+
+func (s *ddlSource) Tables(ctx context.Context) ([]string, error) {
+	return s.names, nil
+}
+
+// ddl 中的视图没有字段信息，不生成
+func (s *ddlSource) Views(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (s *ddlSource) Routines(ctx context.Context) ([]*Routine, error) {
+	return nil, nil
+}
+
+func (s *ddlSource) Table(ctx context.Context, name string) (*tableMeta, error) {
+	t, ok := s.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("%w in %s: %s", ErrTableNotFound, s.path, name)
+	}
+	return t, nil
+}
+
+// 所有表中的外键，没有写引用字段的引用父表的主键
+func (s *ddlSource) ForeignKeys(ctx context.Context) ([]*foreignKey, error) {
+	var fks []*foreignKey
+	for _, name := range s.names {
+		for _, fk := range s.tables[name].ForeignKeys {
+			if len(fk.RefColumns) == 0 {
+				if ref, ok := s.tables[fk.RefTable]; ok {
+					fk.RefColumns = ref.primaryKey()
+				}
+			}
+			fks = append(fks, fk)
+		}
+	}
+	return fks, nil
+}
+
+func (s *ddlSource) Close(ctx context.Context) error {
+	return nil
+}
+
+// 筛选要生成的表，没有指定或者带通配符时从所有的表和视图中匹配
+func selectTables(ctx context.Context, src schemaSource, views, tables, exclude []string) ([]string, error) {
+	tables = trimTables(tables)
+	if len(tables) == 0 || hasWildcard(tables) {
+		all, err := src.Tables(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get all tables failed: %w", err)
+		}
+		tables = matchTables(append(all, views...), tables)
+	}
+	return excludeTables(tables, exclude), nil
+}
+
+func trimTables(tables []string) []string {
+	result := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if table = strings.TrimSpace(table); table != "" {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
+func hasWildcard(patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.ContainsAny(pattern, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+// 从所有的表中筛选出匹配的表，支持 order_* 这种通配符，不传则返回所有的表
+func matchTables(all, patterns []string) []string {
+	if len(patterns) == 0 {
+		return all
+	}
+	var tables []string
+	for _, table := range all {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, table); ok {
+				tables = append(tables, table)
+				break
+			}
+		}
+	}
+	return tables
+}
+
+// 排除表，和 -t 一样不带通配符时只排除同名的表，部分匹配用 *_bak、tmp_* 这种通配符
+func excludeTables(tables, patterns []string) []string {
+	patterns = trimTables(patterns)
+	if len(patterns) == 0 {
+		return tables
+	}
+	var result []string
+	for _, table := range tables {
+		excluded := false
+		for _, pattern := range patterns {
+			if excluded, _ = path.Match(pattern, table); excluded {
+				break
+			}
+		}
+		if !excluded {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
+// 去掉表名前缀，如 t_user_info -> user_info
+func stripTablePrefix(table string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if prefix != "" && gstr.HasPrefix(table, prefix) && len(table) > len(prefix) {
+			return table[len(prefix):]
+		}
+	}
+	return table
+}
+
+// 根据 dsn 的格式解析出数据库连接信息
+func parseDSN(dsn string) (gdb.ConfigNode, error) {
+	if gstr.HasPrefix(dsn, "postgres://") || gstr.HasPrefix(dsn, "postgresql://") {
+		return parsePostgresDSN(dsn)
+	}
+	if gstr.HasPrefix(dsn, "sqlite://") || gstr.HasPrefix(dsn, "sqlite3://") {
+		return parseSqliteDSN(dsn)
+	}
+	// 解析mysql dsn
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return gdb.ConfigNode{}, err
+	}
+	host, port, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return gdb.ConfigNode{}, err
+	}
+	return gdb.ConfigNode{
+		Host: host,
+		Port: port,
+		User: cfg.User,
+		Pass: cfg.Passwd,
+		Name: cfg.DBName,
+		Type: DialectMysql,
+	}, nil
+}
+
+// 配置文件中的 dialect 统一成 gdb 的类型
+func normalizeDialect(dialect string) string {
+	switch gstr.ToLower(gstr.Trim(dialect)) {
+	case "postgres", "postgresql", "pgsql", "pg":
+		return DialectPostgres
+	case "sqlite", "sqlite3":
+		return DialectSqlite
+	default:
+		return DialectMysql
+	}
+}
+
+// 获取表的字段信息
+func tableFields(ctx context.Context, db gdb.DB, dialect, table string) (map[string]*gdb.TableField, error) {
+	switch dialect {
+	case DialectPostgres:
+		return pgTableFields(ctx, db, table)
+	case DialectSqlite:
+		return sqliteTableFields(ctx, db, table)
+	default:
+		return db.TableFields(ctx, table)
+	}
+}
+
+func getMysqlConfig(configPath, key string) (*Mysql, error) {
+	file, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	var configMap ConfigMap
+	if key != "" {
+		err = yaml.Unmarshal(file, &configMap)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := configMap.Mysql[key]; ok {
+			return &c, nil
+		}
+		return nil, fmt.Errorf("key not found")
+	}
+	err = yaml.Unmarshal(file, &config)
+	if err != nil {
+		return nil, err
+	}
+	return &config.Mysql, nil
+}
+
+// 读取配置文件中的 types、conventions、layout，配置文件不存在时忽略
+func getModelConfig(configPath string) (*ModelConfig, error) {
+	var config ModelConfig
+	if !gfile.Exists(configPath) {
+		return &config, nil
+	}
+	file, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(file, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,113 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/text/gstr"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// 解析 sqlite://./schema.db
+func parseSqliteDSN(dsn string) (gdb.ConfigNode, error) {
+	path := gstr.TrimLeftStr(gstr.TrimLeftStr(dsn, "sqlite3://"), "sqlite://")
+	if path == "" {
+		return gdb.ConfigNode{}, fmt.Errorf("invalid sqlite dsn: %s", dsn)
+	}
+	return gdb.ConfigNode{
+		Name: path,
+		Link: path,
+		Type: DialectSqlite,
+	}, nil
+}
+
+// 获取 sqlite 的所有表，过滤掉 sqlite_sequence 等内部表
+func sqliteTables(ctx context.Context, db gdb.DB) ([]string, error) {
+	result, err := db.Ctx(ctx).GetAll(
+		`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(result))
+	for _, m := range result {
+		tables = append(tables, m["name"].String())
+	}
+	return tables, nil
+}
+
+// 通过 PRAGMA table_info/index_list 获取 sqlite 表的字段信息
+func sqliteTableFields(ctx context.Context, db gdb.DB, table string) (map[string]*gdb.TableField, error) {
+	result, err := db.Ctx(ctx).GetAll(fmt.Sprintf("PRAGMA table_info(%s)", sqliteQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	if result.IsEmpty() {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotFound, table)
+	}
+
+	var pkCount int
+	for _, m := range result {
+		if m["pk"].Int() > 0 {
+			pkCount++
+		}
+	}
+
+	fields := make(map[string]*gdb.TableField, len(result))
+	for i, m := range result {
+		field := &gdb.TableField{
+			Index: i,
+			Name:  m["name"].String(),
+			Type:  m["type"].String(),
+			Null:  m["notnull"].Int() == 0,
+		}
+		if !m["dflt_value"].IsNil() {
+			field.Default = gstr.Trim(m["dflt_value"].String(), "'")
+		}
+		if m["pk"].Int() > 0 {
+			field.Key = "PRI"
+			field.Null = false
+			// INTEGER PRIMARY KEY 是 rowid 的别名，会自增
+			if pkCount == 1 && strings.EqualFold(field.Type, "integer") {
+				field.Extra = "auto_increment"
+			}
+		}
+		fields[field.Name] = field
+	}
+
+	// 索引信息，和 mysql 一样用 UNI/MUL 标记
+	indexes, err := sqliteTableIndexes(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+	markIndexKeys(fields, indexes)
+	return fields, nil
+}
+
+// sqlite 类型 -> go 类型，按 sqlite 的类型亲和性规则匹配
+func sqliteTypeName(t string) string {
+	switch {
+	case strings.Contains(t, "bool"):
+		return "bool"
+	case strings.Contains(t, "int"):
+		return "int64"
+	case strings.Contains(t, "char") || strings.Contains(t, "clob") || strings.Contains(t, "text"):
+		return "string"
+	case t == "" || strings.Contains(t, "blob"):
+		return "[]byte"
+	case strings.Contains(t, "real") || strings.Contains(t, "floa") || strings.Contains(t, "doub"):
+		return "float64"
+	case strings.Contains(t, "date") || strings.Contains(t, "time"):
+		return "time.Time"
+	case strings.Contains(t, "numeric") || strings.Contains(t, "decimal"):
+		return "float64"
+	default:
+		return "string"
+	}
+}
+
+func sqliteQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
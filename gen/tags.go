@@ -0,0 +1,140 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/text/gstr"
+	"github.com/xuri/excelize/v2"
+)
+
+// 字段名的命名风格
+const (
+	TagStyleSnake    = "snake"    // user_id
+	TagStyleCamel    = "camel"    // userId
+	TagStyleOriginal = "original" // 和数据库字段名一致
+)
+
+// xlsx 标签中列和列名的分隔符，和 WriteXlsx 的 xlsxTagSep 一致
+const xlsxTagSep = "-"
+
+// StructTag 除了 gorm 之外额外生成的标签
+type StructTag struct {
+	Name  string // json、form、xlsx、validate
+	Style string // 命名风格，默认 snake
+}
+
+// ParseTags 解析 -tags json,form:camel,xlsx，标签后面可以单独指定命名风格
+func ParseTags(s, defaultStyle string) ([]StructTag, error) {
+	if defaultStyle == "" {
+		defaultStyle = TagStyleSnake
+	}
+	if err := checkTagStyle(defaultStyle); err != nil {
+		return nil, err
+	}
+	var tags []StructTag
+	for _, item := range strings.Split(s, ",") {
+		item = gstr.Trim(item)
+		if item == "" {
+			continue
+		}
+		tag := StructTag{Name: item, Style: defaultStyle}
+		if i := strings.Index(item, ":"); i >= 0 {
+			tag.Name, tag.Style = item[:i], item[i+1:]
+			if err := checkTagStyle(tag.Style); err != nil {
+				return nil, err
+			}
+		}
+		switch tag.Name {
+		case "json", "form", "xlsx", "validate":
+		default:
+			return nil, fmt.Errorf("unsupported tag: %s", tag.Name)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func checkTagStyle(style string) error {
+	switch style {
+	case TagStyleSnake, TagStyleCamel, TagStyleOriginal:
+		return nil
+	}
+	return fmt.Errorf("unsupported tag style: %s", style)
+}
+
+// 生成额外的标签，如 json:"user_id" form:"user_id"，autoValue 表示字段由 gorm 自动赋值
+func genStructTags(field *gdb.TableField, comment string, tags []StructTag, autoValue bool) []string {
+	var result []string
+	for _, tag := range tags {
+		var value string
+		switch tag.Name {
+		case "json", "form":
+			value = tagFieldName(field.Name, tag.Style)
+		case "xlsx":
+			value = xlsxTagValue(field, comment)
+		case "validate":
+			value = validateTagValue(field, autoValue)
+		}
+		if value == "" {
+			continue
+		}
+		result = append(result, fmt.Sprintf(`%s:"%s"`, tag.Name, value))
+	}
+	return result
+}
+
+func tagFieldName(name, style string) string {
+	switch style {
+	case TagStyleCamel:
+		return gstr.CaseCamelLower(name)
+	case TagStyleOriginal:
+		return name
+	default:
+		return gstr.CaseSnake(name)
+	}
+}
+
+// xlsx 标签，和 WriteXlsx 的约定一致：列-列名，列根据字段顺序生成，列名取注释
+func xlsxTagValue(field *gdb.TableField, comment string) string {
+	column, err := excelize.ColumnNumberToName(field.Index + 1)
+	if err != nil {
+		return ""
+	}
+	// 注释可能很长，只取第一段作为列名，如 状态: 1-待支付 -> 状态
+	header := comment
+	if i := strings.IndexAny(header, ":：,，;；(（ "); i >= 0 {
+		header = header[:i]
+	}
+	if header == "" {
+		header = field.Name
+	}
+	header = gstr.Replace(header, xlsxTagSep, "_")
+	header = gstr.Replace(header, `"`, "")
+	header = gstr.Replace(header, "`", "")
+	return column + xlsxTagSep + header
+}
+
+// validate 标签，非空且没有默认值的字符串、时间字段必填，字符串限制长度
+// 数字和布尔值的零值也是合法的，不加 required
+func validateTagValue(field *gdb.TableField, autoValue bool) string {
+	if autoValue || gstr.ContainsI(field.Extra, "auto_increment") {
+		return ""
+	}
+	var (
+		rules []string
+		t     = baseTypeName(field.Type)
+		isStr = strings.Contains(t, "char") || strings.Contains(t, "text") || t == "enum" || t == "set"
+	)
+	if !field.Null && field.Default == nil && (isStr || strings.Contains(t, "date") || strings.Contains(t, "time")) {
+		rules = append(rules, "required")
+	}
+	if size := typeSize(field.Type); size != "" && isStr {
+		if len(rules) == 0 {
+			rules = append(rules, "omitempty")
+		}
+		rules = append(rules, "max="+size)
+	}
+	return strings.Join(rules, ",")
+}
@@ -0,0 +1,67 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/database/gdb"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags("json, form:camel,xlsx", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StructTag{{"json", TagStyleSnake}, {"form", TagStyleCamel}, {"xlsx", TagStyleSnake}}
+	if len(tags) != len(want) {
+		t.Fatalf("unexpected tags: %+v", tags)
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Errorf("tags[%d] = %+v, want %+v", i, tags[i], want[i])
+		}
+	}
+	if _, err = ParseTags("yaml", ""); err == nil {
+		t.Error("expected error for unsupported tag")
+	}
+	if _, err = ParseTags("json:kebab", ""); err == nil {
+		t.Error("expected error for unsupported style")
+	}
+}
+
+func TestGenStructTags(t *testing.T) {
+	tags := []StructTag{{"json", TagStyleCamel}, {"form", TagStyleSnake}, {"xlsx", TagStyleSnake}, {"validate", TagStyleSnake}}
+	cases := []struct {
+		field   *gdb.TableField
+		comment string
+		want    string
+	}{
+		{&gdb.TableField{Index: 0, Name: "id", Type: "bigint", Extra: "auto_increment"}, "主键", `json:"id" form:"id" xlsx:"A-主键"`},
+		{&gdb.TableField{Index: 1, Name: "user_name", Type: "varchar(32)"}, "用户名", `json:"userName" form:"user_name" xlsx:"B-用户名" validate:"required,max=32"`},
+		{&gdb.TableField{Index: 2, Name: "status", Type: "tinyint", Default: "1"}, "状态: 1-待支付 2-已支付", `json:"status" form:"status" xlsx:"C-状态"`},
+		{&gdb.TableField{Index: 26, Name: "remark", Type: "varchar(255)", Null: true}, "", `json:"remark" form:"remark" xlsx:"AA-remark" validate:"omitempty,max=255"`},
+	}
+	for _, c := range cases {
+		if got := strings.Join(genStructTags(c.field, c.comment, tags, false), " "); got != c.want {
+			t.Errorf("%s: tags = %s, want %s", c.field.Name, got, c.want)
+		}
+	}
+}
+
+func TestValidateTagConventions(t *testing.T) {
+	opt := &ModelOptions{Dialect: DialectMysql, Nullable: NullablePointer, Conventions: DefaultConventions(), Tags: []StructTag{{"validate", TagStyleSnake}}}
+	for _, field := range []*gdb.TableField{
+		{Name: "created_at", Type: "datetime"},
+		{Name: "updated_at", Type: "timestamp"},
+		{Name: "deleted_at", Type: "datetime"},
+	} {
+		column := genColumn(&tableMeta{Name: "user_info"}, field, opt)
+		if strings.Contains(column.Tag, "validate") {
+			t.Errorf("%s: unexpected validate tag: %s", field.Name, column.Tag)
+		}
+	}
+	column := genColumn(&tableMeta{Name: "user_info"}, &gdb.TableField{Name: "paid_at", Type: "datetime"}, opt)
+	if !strings.Contains(column.Tag, `validate:"required"`) {
+		t.Errorf("paid_at: tag = %s", column.Tag)
+	}
+}
@@ -0,0 +1,346 @@
+package gen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gogf/gf/text/gstr"
+)
+
+// 模板文件的后缀，model.go.tmpl 生成 user_info_gen.go，dao.go.tmpl 生成 user_info_dao_gen.go，
+// custom.go.tmpl 生成 user_info.go，其他的如 repo.go.tmpl 生成 user_info_repo.go
+const (
+	templateExt    = ".tmpl"
+	modelTemplate  = "model.go.tmpl"
+	daoTemplate    = "dao.go.tmpl"
+	customTemplate = "custom.go.tmpl"
+)
+
+// 不按表生成的模板，routines.go.tmpl 生成 routines_gen.go，json.go.tmpl 生成 json 类型的文件，
+// init.go.tmpl 生成 dao 包中的 init.go
+const (
+	routineTemplate  = "routines.go.tmpl"
+	jsonTemplate     = "json.go.tmpl"
+	dbClientTemplate = "init.go.tmpl"
+)
+
+// 已存在的文件的处理方式
+const (
+	writeDefault   = ""          // 按 overwrite 策略处理
+	writeGenerated = "generated" // 带 Code generated 注释的文件，总是覆盖
+	writeOnce      = "once"      // 只在不存在时生成，用来写自定义的方法
+)
+
+// go 约定的生成文件的注释，见 https://golang.org/s/generatedcode
+var generatedRegex = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// TemplateData 模板中可以使用的数据，每个表生成一份
+type TemplateData struct {
+	Package      string      // model 的包名
+	DaoPackage   string      // dao 的包名，和 model 在同一个包中时相同
+	ModelImport  string      // dao 中导入 model 包的路径，在同一个包中时为空
+	Dialect      string      // 数据库类型，mysql、pgsql、sqlite
+	Imports      []string    // 结构体字段和枚举用到的包，如 time、database/sql
+	DaoImports   []string    // dao 方法的参数用到的包，不含 gorm 和 model 包
+	Table        string      // 表名
+	Name         string      // 去掉前缀后的驼峰名，如 UserInfo
+	ModelName    string      // 结构体名，如 UserInfoModel
+	DaoName      string      // dao 的结构体名，如 userInfoDao
+	StructDefine string      // 按列对齐好的结构体定义
+	Columns      []*Column   // 按表中顺序排列的字段
+	PrimaryKeys  []*Column   // 主键字段，按主键中的顺序，联合主键时有多个
+	KeyName      string      // 联合主键的结构体名，如 UserRoleKey，单个主键时为空
+	Indexes      []*Index    // 索引，包括主键
+	Finders      []*Finder   // 根据索引生成的查询方法
+	Relations    []*Relation // 根据外键生成的关联
+	Enums        []*Enum     // 根据 enum、set 类型和注释约定生成的枚举
+	View         bool        // 视图，dao 只生成查询方法
+}
+
+// Model 在 dao 中引用 model 包中的类型，在同一个包中时原样返回，如 *UserInfoStatus -> *model.UserInfoStatus
+func (d *TemplateData) Model(typeName string) string {
+	if d.ModelImport == "" {
+		return typeName
+	}
+	name := strings.TrimLeft(typeName, "*[]")
+	if !token.IsIdentifier(name) || isBuiltinType(name) || name == "error" || name == "any" {
+		return typeName
+	}
+	return typeName[:len(typeName)-len(name)] + d.Package + "." + name
+}
+
+// dao 方法的参数用到的类型
+func (d *TemplateData) daoTypes() []string {
+	var types []string
+	for _, column := range d.PrimaryKeys {
+		types = append(types, column.GoType)
+	}
+	for _, finder := range d.Finders {
+		for _, column := range finder.Columns {
+			types = append(types, column.BaseType)
+		}
+	}
+	return types
+}
+
+// Column 模板中的字段信息
+type Column struct {
+	Name          string    // 数据库字段名，如 user_id
+	GoName        string    // 结构体字段名，如 UserId
+	GoType        string    // Go 类型，可空字段已按 nullable 策略处理，如 *string
+	BaseType      string    // 不考虑可空的 Go 类型，用作查询参数，如 string
+	DBType        string    // 数据库类型，如 varchar(64)
+	Tag           string    // 完整的结构体标签，不含反引号
+	Comment       string    // 注释，已去掉换行
+	Nullable      bool      // 是否可以为 NULL，主键总是 false
+	PrimaryKey    bool      // 是否主键
+	Unique        bool      // 是否有单列的唯一索引
+	AutoIncrement bool      // 是否自增
+	HasDefault    bool      // 是否有默认值
+	Default       string    // 默认值
+	Enum          *Enum     // 字段的枚举类型，不是枚举时为 nil
+	JSONType      *JSONType // json 字段自定义的类型，生成了 Scanner 和 Valuer，没有时为 nil
+}
+
+// Finder 根据索引生成的查询方法
+type Finder struct {
+	Name    string    // 方法名，如 GetByEmail、ListByOrgIdAndStatus、ListByOrgIds
+	Unique  bool      // 唯一索引，返回单条记录
+	Batch   bool      // 单列索引的批量查询，参数为切片，用 IN 查询
+	Columns []*Column // 索引中的字段，按索引中的顺序
+}
+
+// 模板中可以使用的函数
+var templateFuncs = template.FuncMap{
+	"camel":      gstr.CaseCamel,
+	"camelLower": gstr.CaseCamelLower,
+	"snake":      gstr.CaseSnake,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"join":       strings.Join,
+	"param":      paramName,
+	"plural":     plural,
+	"quote":      strconv.Quote,
+}
+
+// 字段名作为函数参数名，如 UserId -> userId，和关键字、dao 方法中的变量或者 model 包重名时加上 _
+func paramName(name string) string {
+	name = gstr.CaseCamelLower(name)
+	switch {
+	case token.IsKeyword(name), name == "ctx", name == "d", name == "r", name == "in", name == "model", name == "err", name == "conn":
+		return name + "_"
+	}
+	return name
+}
+
+// 简单的复数形式，用于批量查询的方法名和参数名，如 OrgId -> OrgIds、Status -> Statuses
+func plural(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// 根据索引生成查询方法，主键已经有 GetByPK，只生成批量查询；同名的方法只保留第一个，唯一索引优先
+func genFinders(columns []*Column, indexes []*Index) []*Finder {
+	columnMap := make(map[string]*Column, len(columns))
+	for _, column := range columns {
+		columnMap[column.Name] = column
+	}
+	sorted := make([]*Index, len(indexes))
+	copy(sorted, indexes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return (sorted[i].Primary || sorted[i].Unique) && !(sorted[j].Primary || sorted[j].Unique)
+	})
+
+	var (
+		finders []*Finder
+		names   = make(map[string]bool)
+	)
+	add := func(f *Finder) {
+		if !names[f.Name] {
+			names[f.Name] = true
+			finders = append(finders, f)
+		}
+	}
+	for _, index := range sorted {
+		cols := make([]*Column, 0, len(index.Columns))
+		goNames := make([]string, 0, len(index.Columns))
+		for _, name := range index.Columns {
+			column, ok := columnMap[name]
+			if !ok {
+				break
+			}
+			cols = append(cols, column)
+			goNames = append(goNames, column.GoName)
+		}
+		// 表达式索引等找不到字段的跳过
+		if len(cols) == 0 || len(cols) != len(index.Columns) {
+			continue
+		}
+		if !index.Primary {
+			prefix := "ListBy"
+			if index.Unique {
+				prefix = "GetBy"
+			}
+			add(&Finder{Name: prefix + strings.Join(goNames, "And"), Unique: index.Unique, Columns: cols})
+		}
+		if len(cols) == 1 {
+			add(&Finder{Name: "ListBy" + plural(goNames[0]), Batch: true, Columns: cols})
+		}
+	}
+	return finders
+}
+
+// 一个模板生成一个文件
+type fileTemplate struct {
+	name string
+	tpl  *template.Template
+}
+
+// 每个表生成的文件
+type genFile struct {
+	path    string
+	content string
+	mode    string // 已存在时的处理方式
+	legacy  string // writeOnce 的文件中有这个内容时，是旧版本生成的完整文件，按 overwrite 策略替换
+	shared  bool   // 多个表共用的文件，一次只生成一次，如 json 类型的文件
+}
+
+// 读取内置的模板，templateDir 中同名的模板会覆盖内置的，其他的模板会额外生成文件
+func loadTemplates(templateDir string) ([]*fileTemplate, error) {
+	sources := make(map[string]string)
+	err := fs.WalkDir(defaultTemplates, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := defaultTemplates.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sources[filepath.Base(path)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if templateDir != "" {
+		files, err := filepath.Glob(filepath.Join(templateDir, "*"+templateExt))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no %s template found in %s", templateExt, templateDir)
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			sources[filepath.Base(file)] = string(content)
+		}
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	templates := make([]*fileTemplate, 0, len(names))
+	for _, name := range names {
+		tpl, err := template.New(name).Funcs(templateFuncs).Parse(sources[name])
+		if err != nil {
+			return nil, fmt.Errorf("parse template %s failed: %w", name, err)
+		}
+		templates = append(templates, &fileTemplate{name: name, tpl: tpl})
+	}
+	return templates, nil
+}
+
+// 按名字查找模板
+func findTemplate(templates []*fileTemplate, name string) (*fileTemplate, error) {
+	for _, t := range templates {
+		if t.name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("template %s not found", name)
+}
+
+// 是否每个表生成一个文件
+func (t *fileTemplate) perTable() bool {
+	switch t.name {
+	case routineTemplate, jsonTemplate, dbClientTemplate:
+		return false
+	}
+	return true
+}
+
+// 模板生成的文件名，model.go.tmpl -> user_info_gen.go，dao.go.tmpl -> user_info_dao_gen.go，
+// custom.go.tmpl -> user_info.go，repo.go.tmpl -> user_info_repo.go
+func (t *fileTemplate) fileName(base string) string {
+	name := strings.TrimSuffix(t.name, templateExt)
+	ext := filepath.Ext(name)
+	switch t.name {
+	case modelTemplate:
+		return base + "_gen" + ext
+	case daoTemplate:
+		return base + "_dao_gen" + ext
+	case customTemplate:
+		return base + ext
+	}
+	return base + "_" + strings.TrimSuffix(name, ext) + ext
+}
+
+// 模板生成的目录，model.go.tmpl 生成在 model 的目录，其他的生成在 dao 的目录
+func (t *fileTemplate) dir(opt *ModelOptions) string {
+	if t.name == modelTemplate {
+		return opt.modelPath()
+	}
+	return opt.daoPath()
+}
+
+// 生成的文件的处理方式
+func (t *fileTemplate) writeMode(content string) string {
+	switch {
+	case t.name == customTemplate:
+		return writeOnce
+	case generatedRegex.MatchString(content):
+		return writeGenerated
+	}
+	return writeDefault
+}
+
+// 执行模板，生成 go 文件时会格式化
+func (t *fileTemplate) render(path string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("execute template %s failed: %w", t.name, err)
+	}
+	if filepath.Ext(path) != ".go" {
+		return buf.String(), nil
+	}
+	bts, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("format %s failed: %w", path, err)
+	}
+	return string(bts), nil
+}
@@ -0,0 +1,235 @@
+package gen
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/os/gfile"
+)
+
+func TestGenModelTemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	templates := map[string]string{
+		"model.go.tmpl": `package {{.Package}}
+
+// {{.ModelName}} {{.Table}}
+type {{.ModelName}} struct {
+{{- range .Columns}}
+	{{.GoName}} {{.GoType}} ` + "`{{.Tag}}`" + `
+{{- end}}
+}
+`,
+		"repo.go.tmpl": `package {{.Package}}
+
+// primary keys: {{range .PrimaryKeys}}{{.Name}} {{end}}
+{{- range .Indexes}}{{if .Unique}}
+// unique: {{join .Columns ","}}{{end}}{{end}}
+func {{camelLower .Name}}Columns() []string {
+	return []string{ {{- range .Columns}}"{{.Name}}", {{end -}} }
+}
+`,
+		// 不按表生成的模板同样可以覆盖
+		"routines.go.tmpl": `package {{.Package}}
+
+// routines: {{range .Routines}}{{.Name}} {{end}}
+`,
+	}
+	for name, content := range templates {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	genPath := filepath.Join(t.TempDir(), "dao")
+	opts := ModelOptions{DDL: "testdata/*.sql", Path: genPath, Tables: []string{"tag"}, TemplateDir: dir}
+	summary, err := GenModel(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Created) != 4 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	model, _ := ioutil.ReadFile(filepath.Join(genPath, "tag_gen.go"))
+	if !strings.Contains(string(model), "// TagModel tag") || strings.Contains(string(model), "TableName") {
+		t.Errorf("model.go.tmpl should override the default template:\n%s", model)
+	}
+	repo, _ := ioutil.ReadFile(filepath.Join(genPath, "tag_repo.go"))
+	for _, want := range []string{"// primary keys: id", "// unique: name", "func tagColumns() []string", `"id", "name"`} {
+		if !strings.Contains(string(repo), want) {
+			t.Errorf("repo missing %q:\n%s", want, repo)
+		}
+	}
+
+	loaded, err := loadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routineOpt := &ModelOptions{Dialect: DialectMysql, Path: genPath, Package: "dao"}
+	file, err := genRoutineFile(genRoutines(mysqlTestRoutines(), routineOpt), loaded, routineOpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(file.content, "// routines: calc_order cleanup settle") {
+		t.Errorf("routines.go.tmpl should override the default template:\n%s", file.content)
+	}
+}
+
+func TestFileTemplateName(t *testing.T) {
+	cases := map[string]string{
+		"model.go.tmpl":  "user_info_gen.go",
+		"custom.go.tmpl": "user_info.go",
+		"repo.go.tmpl":   "user_info_repo.go",
+		"api.md.tmpl":    "user_info_api.md",
+	}
+	for name, want := range cases {
+		if got := (&fileTemplate{name: name}).fileName("user_info"); got != want {
+			t.Errorf("%s: file name = %s, want %s", name, got, want)
+		}
+	}
+}
+
+// 生成的 dao 需要能编译，下载 gorm 失败时跳过
+func TestGenModelCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip compiling generated models in short mode")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                              "module example.com/fgen\n\ngo 1.18\n\nrequire gorm.io/gorm v1.25.12\n",
+		"dao/org_settings.go":                 "package dao\n\ntype OrgSettings struct {\n\tTheme string `json:\"theme\"`\n}\n",
+		"repository/db/model/org_settings.go": "package model\n\ntype OrgSettings struct {\n\tTheme string `json:\"theme\"`\n}\n",
+		"types/types.go":                      "package types\n\ntype RoleExtra struct {\n\tColor string `json:\"color\"`\n}\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// json 字段分别映射到同包和其他包中的类型，model 和 dao 分别生成在同一个包和不同的包中
+	types := map[string]string{"org.settings": "OrgSettings", "role.extra": "example.com/fgen/types.RoleExtra"}
+	for _, opts := range []ModelOptions{
+		{DDL: "testdata/*.sql", Path: filepath.Join(dir, "dao"), Types: types},
+		{DDL: "testdata/*.sql", ModelPath: filepath.Join(dir, "repository/db/model"), DaoPath: filepath.Join(dir, "repository/db/dao"), Types: types},
+	} {
+		if _, err := GenModel(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 存储过程和函数的 RoutineDao
+	templates, err := loadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dialect, routines := range map[string][]*Routine{DialectMysql: mysqlTestRoutines(), DialectPostgres: pgTestRoutines()} {
+		opt := &ModelOptions{Dialect: dialect, Path: filepath.Join(dir, "routines", dialect), Package: dialect}
+		file, err := genRoutineFile(genRoutines(routines, opt), templates, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = gfile.PutContents(file.path, file.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goCmd := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		return cmd.CombinedOutput()
+	}
+	if out, err := goCmd("mod", "tidy"); err != nil {
+		t.Skipf("download gorm failed: %v\n%s", err, out)
+	}
+	if out, err := goCmd("vet", "./..."); err != nil {
+		t.Fatalf("generated models do not compile: %v\n%s", err, out)
+	}
+}
+
+func TestGenFinders(t *testing.T) {
+	tables, err := readDDLTables("testdata/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := tables[0]
+	finders := genFinders(genColumns(meta, &ModelOptions{Dialect: DialectMysql}), meta.Indexes)
+	var got []string
+	for _, f := range finders {
+		params := make([]string, 0, len(f.Columns))
+		for _, c := range f.Columns {
+			params = append(params, c.BaseType)
+		}
+		got = append(got, f.Name+"("+strings.Join(params, ",")+")")
+	}
+	want := "ListByIds(uint64) GetByEmail(string) ListByEmails(string) ListByOrgIdAndStatus(int32,UserInfoStatus)"
+	if strings.Join(got, " ") != want {
+		t.Errorf("finders = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	for name, want := range map[string]string{"OrgId": "OrgIds", "Status": "Statuses", "Category": "Categories", "Key": "Keys"} {
+		if got := plural(name); got != want {
+			t.Errorf("plural(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestGenModelCompositeKey(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/fgen\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modelPath, daoPath := filepath.Join(dir, "db", "model"), filepath.Join(dir, "db", "dao")
+	opts := ModelOptions{DDL: "testdata/*.sql", ModelPath: modelPath, DaoPath: daoPath, Tables: []string{"user_role", "access_log"}}
+	if _, err := GenModel(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	model, _ := ioutil.ReadFile(filepath.Join(modelPath, "user_role_gen.go"))
+	dao, _ := ioutil.ReadFile(filepath.Join(daoPath, "user_role_dao_gen.go"))
+	for content, wants := range map[string][]string{
+		string(model): {
+			"package model",
+			"type UserRoleKey struct",
+			"func (m *UserRoleModel) Key() UserRoleKey",
+			`gorm:"column:user_id;type:bigint;primaryKey;autoIncrement:false"`,
+		},
+		string(dao): {
+			"package dao",
+			`"example.com/fgen/db/model"`,
+			"GetByPK(ctx context.Context, userId int64, roleId int32) (*model.UserRoleModel, error)",
+			"DeleteByPK(ctx context.Context, userId int64, roleId int32)",
+			"ListByPKs(ctx context.Context, keys []model.UserRoleKey)",
+			`columns := []clause.Column{{Name: "user_id"}, {Name: "role_id"}}`,
+			`Where(clause.IN{Column: columns, Values: values})`,
+			`Where("? = ?", clause.Column{Name: "role_id"}, roleId)`,
+		},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("user_role missing %q:\n%s", want, content)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(daoPath, "init.go")); err != nil {
+		t.Errorf("db client should be generated in dao: %v", err)
+	}
+
+	content, _ := ioutil.ReadFile(filepath.Join(daoPath, "access_log_dao_gen.go"))
+	if !strings.Contains(string(content), "func (d *accessLogDao) Delete(ctx context.Context, cond *model.AccessLogModel) error") {
+		t.Errorf("access_log dao missing Delete:\n%s", content)
+	}
+	for _, unwanted := range []string{"GetByPK", "DeleteByPK", "Key()"} {
+		if strings.Contains(string(content), unwanted) {
+			t.Errorf("table without primary key should not have %s:\n%s", unwanted, content)
+		}
+	}
+	if !strings.Contains(string(content), "ListByUserIds(ctx context.Context, userIds []int64)") {
+		t.Errorf("access_log missing index finder:\n%s", content)
+	}
+}
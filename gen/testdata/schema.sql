@@ -0,0 +1,58 @@
+-- 用户表
+DROP TABLE IF EXISTS `user_info`;
+CREATE TABLE IF NOT EXISTS `user_info` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `email` varchar(64) NOT NULL DEFAULT '' COMMENT '邮箱, 唯一',
+  `nick_name` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL,
+  `status` enum('draft','published') NOT NULL DEFAULT 'draft',
+  `balance` decimal(10,2) NOT NULL DEFAULT 0.00,
+  `org_id` int NOT NULL,
+  `updated_at` datetime(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_email` (`email`),
+  KEY `idx_org_status` (`org_id`, `status`),
+  CONSTRAINT `fk_user_org` FOREIGN KEY (`org_id`) REFERENCES `org` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表';
+
+/* 标签表 */
+CREATE TABLE tag (id int PRIMARY KEY, name varchar(16) UNIQUE);
+
+-- 用户角色表，联合主键
+CREATE TABLE `user_role` (
+  `user_id` bigint NOT NULL,
+  `role_id` int NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`user_id`, `role_id`)
+);
+
+-- 访问日志，没有主键
+CREATE TABLE `access_log` (
+  `user_id` bigint NOT NULL,
+  `path` varchar(255) NOT NULL,
+  `method` tinyint NOT NULL DEFAULT 1 COMMENT '请求方法: 1-GET 2-POST 3-其他',
+  `flags` set('slow','error') DEFAULT NULL,
+  KEY `idx_user` (`user_id`)
+);
+
+-- 组织和角色，用于生成关联
+CREATE TABLE `org` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(64) NOT NULL,
+  `settings` json DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `role` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(32) NOT NULL,
+  `extra` json NOT NULL,
+  `meta` json DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `user_role_link` (
+  `user_id` bigint unsigned NOT NULL,
+  `role_id` int NOT NULL REFERENCES role (id) ON DELETE CASCADE ON UPDATE NO ACTION,
+  PRIMARY KEY (`user_id`, `role_id`),
+  CONSTRAINT `fk_link_user` FOREIGN KEY (`user_id`) REFERENCES `user_info` (`id`) ON DELETE CASCADE
+);
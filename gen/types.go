@@ -0,0 +1,161 @@
+package gen
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gogf/gf/database/gdb"
+	"github.com/gogf/gf/text/gregex"
+	"github.com/gogf/gf/text/gstr"
+)
+
+// mysql 类型 -> go 类型，key 为去掉长度后的类型
+var mysqlTypes = map[string]string{
+	"bool":       "bool",
+	"boolean":    "bool",
+	"tinyint":    "int8",
+	"smallint":   "int16",
+	"mediumint":  "int32",
+	"int":        "int32",
+	"integer":    "int32",
+	"bigint":     "int64",
+	"float":      "float32",
+	"double":     "float64",
+	"real":       "float64",
+	"decimal":    "float64",
+	"numeric":    "float64",
+	"date":       "time.Time",
+	"datetime":   "time.Time",
+	"timestamp":  "time.Time",
+	"time":       "string",
+	"year":       "int16",
+	"char":       "string",
+	"varchar":    "string",
+	"tinytext":   "string",
+	"text":       "string",
+	"mediumtext": "string",
+	"longtext":   "string",
+	"enum":       "string",
+	"set":        "string",
+	"json":       "datatypes.JSON",
+	"binary":     "[]byte",
+	"varbinary":  "[]byte",
+	"tinyblob":   "[]byte",
+	"blob":       "[]byte",
+	"mediumblob": "[]byte",
+	"longblob":   "[]byte",
+	"bit":        "uint64",
+}
+
+// unsigned 的整数类型
+var mysqlUnsignedTypes = map[string]string{
+	"tinyint":   "uint8",
+	"smallint":  "uint16",
+	"mediumint": "uint32",
+	"int":       "uint32",
+	"integer":   "uint32",
+	"bigint":    "uint64",
+}
+
+// mysql 类型 -> go 类型
+func mysqlTypeName(field *gdb.TableField) string {
+	full := gstr.ToLower(gstr.Trim(field.Type))
+	t := baseTypeName(full)
+	unsigned := gstr.Contains(full, "unsigned")
+
+	var typeName string
+	switch {
+	// tinyint(1) 和 bit(1) 一般用来存布尔值
+	case t == "tinyint" && gstr.HasPrefix(full, "tinyint(1)"):
+		typeName = "bool"
+	case t == "bit" && (full == "bit" || gstr.HasPrefix(full, "bit(1)")):
+		typeName = "bool"
+	case unsigned && mysqlUnsignedTypes[t] != "":
+		typeName = mysqlUnsignedTypes[t]
+	case mysqlTypes[t] != "":
+		typeName = mysqlTypes[t]
+	default:
+		switch {
+		case strings.Contains(t, "int"):
+			typeName = "int64"
+		case strings.Contains(t, "text") || strings.Contains(t, "char"):
+			typeName = "string"
+		case strings.Contains(t, "float") || strings.Contains(t, "double"):
+			typeName = "float64"
+		case strings.Contains(t, "bool"):
+			typeName = "bool"
+		case strings.Contains(t, "binary") || strings.Contains(t, "blob"):
+			typeName = "[]byte"
+		case strings.Contains(t, "date") || strings.Contains(t, "time"):
+			typeName = "time.Time"
+		default:
+			typeName = "string"
+		}
+	}
+	return typeName
+}
+
+// 去掉长度和 unsigned 等修饰，如 bigint(20) unsigned -> bigint
+func baseTypeName(t string) string {
+	t, _ = gregex.ReplaceString(`\(.+\)`, "", t)
+	t = strings.Split(gstr.Trim(t), " ")[0]
+	return gstr.ToLower(t)
+}
+
+// 配置文件中 types 的自定义类型，优先级：table.column > 完整类型 > 带 unsigned 的类型 > 去掉长度的类型
+func overrideTypeName(table string, field *gdb.TableField, types map[string]string) (typeName, importPath string, ok bool) {
+	if len(types) == 0 {
+		return "", "", false
+	}
+	full := gstr.ToLower(gstr.Trim(field.Type))
+	t := baseTypeName(full)
+	keys := []string{table + "." + field.Name, full}
+	if gstr.Contains(full, "unsigned") {
+		keys = append(keys, t+" unsigned")
+	}
+	keys = append(keys, t)
+	for _, key := range keys {
+		if v, ok := types[key]; ok && v != "" {
+			typeName, importPath = parseGoType(v)
+			return typeName, importPath, true
+		}
+	}
+	return "", "", false
+}
+
+// 解析带包路径的类型，如 github.com/shopspring/decimal.Decimal -> decimal.Decimal、github.com/shopspring/decimal
+func parseGoType(s string) (typeName, importPath string) {
+	s = gstr.Trim(s)
+	var prefix string
+	for {
+		switch {
+		case gstr.HasPrefix(s, "*"):
+			prefix += "*"
+			s = s[1:]
+			continue
+		case gstr.HasPrefix(s, "[]"):
+			prefix += "[]"
+			s = s[2:]
+			continue
+		}
+		break
+	}
+	index := strings.LastIndex(s, ".")
+	if index < 0 || strings.LastIndex(s, "/") > index {
+		return prefix + s, ""
+	}
+	importPath = s[:index]
+	return prefix + packageName(importPath) + "." + s[index+1:], importPath
+}
+
+// 包路径对应的包名，兼容 gopkg.in/guregu/null.v4、github.com/x/y/v2 这种带版本的路径
+func packageName(importPath string) string {
+	name := path.Base(importPath)
+	if gregex.IsMatchString(`^v\d+$`, name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+	if gregex.IsMatchString(`\.v\d+$`, name) {
+		name = name[:strings.LastIndex(name, ".")]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
@@ -0,0 +1,65 @@
+package gen
+
+import (
+	"context"
+
+	"github.com/gogf/gf/database/gdb"
+)
+
+// mysql 的 SHOW TABLES 包括视图，按类型分别查询
+const mysqlTablesSql = `
+SELECT TABLE_NAME AS name
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = ?
+ORDER BY TABLE_NAME`
+
+const pgViewsSql = `
+SELECT table_name AS name
+FROM information_schema.views
+WHERE table_schema = current_schema()
+ORDER BY table_name`
+
+const sqliteViewsSql = `SELECT name FROM sqlite_master WHERE type = 'view' ORDER BY name`
+
+// 数据库中的视图，字段从 information_schema 中读取，和表一样生成 model，dao 只有查询方法
+func (s *dbSource) Views(ctx context.Context) ([]string, error) {
+	var (
+		result gdb.Result
+		err    error
+	)
+	switch s.dialect {
+	case DialectPostgres:
+		result, err = s.db.Ctx(ctx).GetAll(pgViewsSql)
+	case DialectSqlite:
+		result, err = s.db.Ctx(ctx).GetAll(sqliteViewsSql)
+	default:
+		result, err = s.db.Ctx(ctx).GetAll(mysqlTablesSql, "VIEW")
+	}
+	if err != nil {
+		return nil, err
+	}
+	views := resultNames(result)
+	// Table 中视图不读取索引
+	s.views = make(map[string]bool, len(views))
+	for _, view := range views {
+		s.views[view] = true
+	}
+	return views, nil
+}
+
+// mysql 中的表，不包括视图
+func mysqlTables(ctx context.Context, db gdb.DB) ([]string, error) {
+	result, err := db.Ctx(ctx).GetAll(mysqlTablesSql, "BASE TABLE")
+	if err != nil {
+		return nil, err
+	}
+	return resultNames(result), nil
+}
+
+func resultNames(result gdb.Result) []string {
+	names := make([]string, 0, len(result))
+	for _, m := range result {
+		names = append(names, m["name"].String())
+	}
+	return names
+}
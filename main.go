@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/CocaineCong/fgen/gen"
+	"github.com/gogf/gf/os/glog"
 	"github.com/urfave/cli"
 )
 
@@ -49,7 +51,7 @@ func main() {
 				}
 
 				// b := ctx.String("b")
-				return GenProject("", name)
+				return gen.GenProject("", name, os.Stdout)
 			},
 		},
 		{
@@ -71,6 +73,7 @@ func main() {
 
 	if err := app.Run(os.Args); err != nil {
 		fmt.Println(err.Error())
+		os.Exit(1)
 	}
 }
 
@@ -82,15 +85,103 @@ func modelFlag() []cli.Flag {
 		},
 		cli.StringFlag{
 			Name:  "dsn", // Data Source Name
-			Usage: "mysql link dsn , default read config/local/config.yaml",
+			Usage: "mysql/postgres/sqlite link dsn , default read config/local/config.yaml",
+		},
+		cli.StringFlag{
+			Name:  "ddl",
+			Usage: "gen from CREATE TABLE ddl file instead of database, support glob like migrations/*.sql",
+		},
+		cli.StringFlag{
+			Name:  "nullable",
+			Usage: "type of nullable column: pointer(default), sql, null, none",
+		},
+		cli.StringFlag{
+			Name:  "tags",
+			Usage: "extra struct tags: json,form,xlsx,validate, style can be set like json:camel",
+		},
+		cli.StringFlag{
+			Name:  "tag-style",
+			Usage: "naming style of json/form tags: snake(default), camel, original",
 		},
 		cli.StringFlag{
 			Name:  "t",
-			Usage: "gen the tables name, separable use ,",
+			Usage: "gen the tables name, separable use , support pattern like order_*",
+		},
+		cli.StringFlag{
+			Name:  "exclude",
+			Usage: "exclude the tables, separable use , support pattern like tmp_*",
+		},
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "gen all the tables",
+		},
+		cli.BoolFlag{
+			Name:  "all-jobs",
+			Usage: "run all the jobs in the gen section of config.yaml, each job has its own datasource, tables, path and package",
+		},
+		cli.StringFlag{
+			Name:  "strip-prefix",
+			Usage: "strip the table name prefix when gen struct and file name, separable use ,",
+		},
+		cli.StringFlag{
+			Name:  "template-dir",
+			Usage: "dir of *.tmpl text/template files, model.go.tmpl overrides the default one, others gen extra files",
+		},
+		cli.IntFlag{
+			Name:  "j",
+			Usage: "number of tables introspected and generated concurrently, default the number of cpu",
+		},
+		cli.BoolFlag{
+			Name:  "routines",
+			Usage: "gen RoutineDao in routines_gen.go to call the stored procedures and functions",
+		},
+		cli.BoolFlag{
+			Name:  "no-conventions",
+			Usage: "do not gen gorm.DeletedAt, autoCreateTime and autoUpdateTime for deleted_at, created_at and updated_at",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "overwrite the existing files without asking",
+		},
+		cli.BoolFlag{
+			Name:  "skip-existing",
+			Usage: "skip the existing files without asking",
+		},
+		cli.BoolFlag{
+			Name:  "backup",
+			Usage: "backup the existing files to .bak before overwriting",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the files that would be written without writing",
+		},
+		cli.BoolFlag{
+			Name:  "diff",
+			Usage: "print the unified diff between the existing files and the generated files without writing",
+		},
+		cli.BoolFlag{
+			Name:  "check",
+			Usage: "exit non-zero when the generated files differ from the existing files",
 		},
 		cli.StringFlag{
 			Name:  "p",
-			Usage: "model generation path",
+			Usage: "model generation path, the model and dao are generated in the same package",
+		},
+		cli.StringFlag{
+			Name:  "model-path",
+			Usage: "model generation path when the model and dao are in different packages",
+		},
+		cli.StringFlag{
+			Name:  "dao-path",
+			Usage: "dao generation path, the dao imports the model package according to go.mod",
+		},
+		cli.StringFlag{
+			Name:  "layout",
+			Usage: "layout of the model and dao: single, project(repository/db/model and repository/db/dao), default is the layout in config.yaml",
+		},
+		cli.StringFlag{
+			Name:  "manifest",
+			Usage: "fgen.yaml path, the flags override the values in it, default read fgen.yaml in the current directory",
 		},
 		cli.StringFlag{
 			Name:  "c",
@@ -105,25 +196,61 @@ func modelFlag() []cli.Flag {
 
 func modelAction() func(ctx *cli.Context) error {
 	return func(ctx *cli.Context) error {
+		manifestPath := ctx.String("manifest")
+		if manifestPath == "" {
+			manifestPath = gen.ManifestFile
+		} else if _, err := os.Stat(manifestPath); err != nil {
+			return err
+		}
+		manifest, err := gen.LoadManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		hasManifest := manifest != nil
+		if !hasManifest {
+			manifest = &gen.Manifest{}
+		}
+
+		// 命令行参数优先，没有设置时使用 fgen.yaml 中的
 		dsn := ctx.String("dsn")
 		if dsn == "" {
 			dsn = ctx.String("dns")
 		}
-		t := ctx.String("t")
-		configPath := ctx.String("c")
-		path := ctx.String("p")
 		key := ctx.String("k")
+		ddl := ctx.String("ddl")
+		// 数据源作为一个整体覆盖，避免 fgen.yaml 中的 ddl、dsn 优先于命令行的 -k
+		if dsn == "" && key == "" && ddl == "" {
+			dsn, key, ddl = manifest.DSN, manifest.Key, manifest.DDL
+		}
+		t := flagValue(ctx, "t", strings.Join(manifest.Tables, ","))
+		configPath := flagValue(ctx, "c", manifest.Config)
+		nullable := flagValue(ctx, "nullable", manifest.Nullable)
+		tagNames := flagValue(ctx, "tags", strings.Join(manifest.Tags, ","))
+		tagStyle := flagValue(ctx, "tag-style", manifest.TagStyle)
 
-		if t == "" {
-			return fmt.Errorf("the table name must be specified")
+		// fgen.yaml 中的表为空时生成所有的表
+		if t == "" && ddl == "" && !hasManifest && !ctx.Bool("all") && !ctx.Bool("all-jobs") {
+			return fmt.Errorf("the table name must be specified, or use -all to gen all the tables")
 		}
 
+		// 指定了 -p 时不使用 fgen.yaml 中的路径、包名和 layout
+		path := ctx.String("p")
+		layout := ctx.String("layout")
+		modelPath, daoPath := manifest.ModelPath, manifest.DaoPath
+		pkg, daoPkg := manifest.Package, manifest.DaoPackage
 		if path == "" {
-			path = DefaultGenModelPath
-			_, err := os.Stat(path)
-			if os.IsNotExist(err) {
-				path = DefaultGenModelPath
+			path = manifest.Path
+			if layout == "" {
+				layout = manifest.Layout
 			}
+		} else {
+			modelPath, daoPath, pkg, daoPkg = "", "", "", ""
+		}
+		if path == "" {
+			path = DefaultGenModelPath
+		} else if layout == "" {
+			// 指定了路径时不使用配置文件中的 layout
+			layout = gen.LayoutSingle
 		}
 
 		if configPath == "" {
@@ -134,7 +261,124 @@ func modelAction() func(ctx *cli.Context) error {
 			key = DefaultKey
 		}
 
-		tables := strings.Split(t, ",")
-		return GenModel(context.Background(), dsn, path, "", configPath, key, tables...)
+		switch nullable {
+		case "":
+			nullable = gen.NullablePointer
+		case gen.NullablePointer, gen.NullableSql, gen.NullableNull, gen.NullableNone:
+		default:
+			return fmt.Errorf("unsupported nullable type: %s", nullable)
+		}
+		tags, err := gen.ParseTags(tagNames, tagStyle)
+		if err != nil {
+			return err
+		}
+		opts := gen.ModelOptions{
+			DSN:         dsn,
+			ConfigPath:  configPath,
+			Key:         key,
+			DDL:         ddl,
+			Path:        path,
+			ModelPath:   flagValue(ctx, "model-path", modelPath),
+			DaoPath:     flagValue(ctx, "dao-path", daoPath),
+			Layout:      layout,
+			Package:     pkg,
+			DaoPackage:  daoPkg,
+			TemplateDir: flagValue(ctx, "template-dir", manifest.TemplateDir),
+			Nullable:    nullable,
+			Tags:        tags,
+			Types:       manifest.Types,
+			Conventions: manifest.Conventions,
+			DryRun:      ctx.Bool("dry-run"),
+			Diff:        ctx.Bool("diff"),
+			Check:       ctx.Bool("check"),
+			Jobs:        ctx.Int("j"),
+			Routines:    manifest.Routines,
+		}
+		opts.Overwrite, err = overwriteMode(ctx)
+		if err != nil {
+			return err
+		}
+		// 显式的 -routines=false 也能关闭 fgen.yaml 中的 routines
+		if ctx.IsSet("routines") {
+			opts.Routines = ctx.Bool("routines")
+		}
+		if ctx.Bool("no-conventions") {
+			opts.Conventions = &gen.Conventions{}
+		}
+		if exclude := flagValue(ctx, "exclude", strings.Join(manifest.Exclude, ",")); exclude != "" {
+			opts.Exclude = strings.Split(exclude, ",")
+		}
+		if prefix := flagValue(ctx, "strip-prefix", strings.Join(manifest.StripPrefix, ",")); prefix != "" {
+			opts.StripPrefix = strings.Split(prefix, ",")
+		}
+		if t != "" && !ctx.Bool("all") {
+			opts.Tables = strings.Split(t, ",")
+		}
+
+		if ctx.Bool("all-jobs") {
+			return genJobs(opts)
+		}
+
+		summary, err := gen.GenModel(context.Background(), opts)
+		if summary != nil {
+			if opts.DryRun || opts.Diff || opts.Check {
+				fmt.Println("dry run, no files written")
+			}
+			summary.Print(os.Stdout)
+		}
+		if err != nil {
+			return err
+		}
+		glog.Print("done!")
+		return nil
+	}
+}
+
+// 执行配置文件 gen 中的所有任务，按任务输出生成的文件
+func genJobs(opts gen.ModelOptions) error {
+	results, err := gen.GenJobs(context.Background(), opts)
+	if len(results) > 0 && (opts.DryRun || opts.Diff || opts.Check) {
+		fmt.Println("dry run, no files written")
+	}
+	for _, result := range results {
+		fmt.Printf("[%s] ", result.Name)
+		if result.Summary != nil {
+			result.Summary.Print(os.Stdout)
+		} else {
+			fmt.Println(result.Err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	glog.Print("done!")
+	return nil
+}
+
+// 命令行参数设置了时使用参数的值，否则使用 fgen.yaml 中的值
+func flagValue(ctx *cli.Context, name, manifest string) string {
+	if v := ctx.String(name); v != "" {
+		return v
+	}
+	return manifest
+}
+
+func overwriteMode(ctx *cli.Context) (string, error) {
+	var modes []string
+	if ctx.Bool("force") {
+		modes = append(modes, gen.OverwriteForce)
+	}
+	if ctx.Bool("skip-existing") {
+		modes = append(modes, gen.OverwriteSkip)
+	}
+	if ctx.Bool("backup") {
+		modes = append(modes, gen.OverwriteBackup)
+	}
+	if len(modes) > 1 {
+		return "", errors.New("only one of -force, -skip-existing and -backup can be set")
+	}
+	if len(modes) == 0 {
+		return gen.OverwriteAsk, nil
 	}
+	return modes[0], nil
 }
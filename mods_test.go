@@ -7,11 +7,13 @@ import (
 	"os/exec"
 	"testing"
 	"time"
+
+	"github.com/CocaineCong/fgen/gen"
 )
 
 func TestGenProject(t *testing.T) {
 	projectPath, _ := os.Getwd()
-	err := GenProject(projectPath, "demo/")
+	err := gen.GenProject(projectPath, "demo/", os.Stdout)
 	if err != nil {
 		fmt.Println("err", err)
 	}